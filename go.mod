@@ -5,5 +5,6 @@ go 1.16
 require (
 	github.com/adshao/go-binance/v2 v2.2.2
 	github.com/cryptellation/models.go v1.1.0
+	github.com/gorilla/websocket v1.2.0
 	github.com/pelletier/go-toml v1.9.2
 )
@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+// weekAnchor is the open time of the first weekly candlestick after epoch, as
+// Binance weekly candlesticks open on Monday at midnight UTC. Other periods
+// are anchored on epoch.
+var weekAnchor = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// FloorCandleTime will return the open time of the candlestick of the period
+// that contains the time, in UTC
+func FloorCandleTime(t time.Time, period int64) (time.Time, error) {
+	anchor, duration, err := candleAnchor(period)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	offset := t.Sub(anchor) % duration
+	if offset < 0 {
+		offset += duration
+	}
+	return t.Add(-offset).UTC(), nil
+}
+
+// CeilCandleTime will return the open time of the first candlestick of the
+// period that opens at or after the time, in UTC
+func CeilCandleTime(t time.Time, period int64) (time.Time, error) {
+	floor, err := FloorCandleTime(t, period)
+	if err != nil || floor.Equal(t) {
+		return floor, err
+	}
+	return floor.Add(time.Duration(period) * time.Second), nil
+}
+
+// StepCandleTime will return the open time of the candlestick n candlesticks
+// after the one that contains the time, or before it if n is negative
+func StepCandleTime(t time.Time, period int64, n int) (time.Time, error) {
+	floor, err := FloorCandleTime(t, period)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return floor.Add(time.Duration(n) * time.Duration(period) * time.Second), nil
+}
+
+// IsCandleTime will return true if the time is the open time of a candlestick
+// of the period
+func IsCandleTime(t time.Time, period int64) (bool, error) {
+	floor, err := FloorCandleTime(t, period)
+	if err != nil {
+		return false, err
+	}
+	return floor.Equal(t), nil
+}
+
+// CountCandles will return the number of candlesticks of the period that open
+// between start and end, both included, as Binance does with time bounds
+func CountCandles(start, end time.Time, period int64) (int64, error) {
+	first, err := CeilCandleTime(start, period)
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := FloorCandleTime(end, period)
+	if err != nil {
+		return 0, err
+	}
+
+	if last.Before(first) {
+		return 0, nil
+	}
+	return int64(last.Sub(first)/(time.Duration(period)*time.Second)) + 1, nil
+}
+
+// candleAnchor will return an open time of the candlesticks of the period and
+// their duration, or an error if the period is not supported by Binance
+func candleAnchor(period int64) (time.Time, time.Duration, error) {
+	if _, err := PeriodToInterval(period); err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: %d", err, period)
+	}
+
+	anchor := time.Unix(0, 0)
+	if period == models.W1 {
+		anchor = weekAnchor
+	}
+	return anchor, time.Duration(period) * time.Second, nil
+}
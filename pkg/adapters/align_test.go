@@ -0,0 +1,119 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+var testCasesFloorCandleTime = []struct {
+	Time   time.Time
+	Period int64
+	Floor  time.Time
+}{
+	{
+		Time:   time.Date(2021, 6, 10, 12, 34, 56, 789000000, time.UTC),
+		Period: models.M1,
+		Floor:  time.Date(2021, 6, 10, 12, 34, 0, 0, time.UTC),
+	},
+	{
+		Time:   time.Date(2021, 6, 10, 12, 34, 56, 0, time.UTC),
+		Period: models.M15,
+		Floor:  time.Date(2021, 6, 10, 12, 30, 0, 0, time.UTC),
+	},
+	{
+		Time:   time.Date(2021, 6, 10, 12, 34, 56, 0, time.FixedZone("UTC+2", 2*60*60)),
+		Period: models.H4,
+		Floor:  time.Date(2021, 6, 10, 8, 0, 0, 0, time.UTC),
+	},
+	{
+		// Thursday, weekly candlestick opens on Monday
+		Time:   time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC),
+		Period: models.W1,
+		Floor:  time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		// Three days candlesticks are anchored on epoch
+		Time:   time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC),
+		Period: models.D3,
+		Floor:  time.Date(2021, 6, 8, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Time:   time.Unix(-1, 0),
+		Period: models.M1,
+		Floor:  time.Unix(-60, 0).UTC(),
+	},
+}
+
+func TestFloorCandleTime(t *testing.T) {
+	for i, test := range testCasesFloorCandleTime {
+		r, err := FloorCandleTime(test.Time, test.Period)
+		if err != nil {
+			t.Error("There should be no error on test", i, ":", err)
+		} else if r != test.Floor {
+			t.Error("Floor of test", i, "should be", test.Floor, "but is", r)
+		}
+	}
+}
+
+func TestFloorCandleTime_InexistantPeriod(t *testing.T) {
+	if _, err := FloorCandleTime(time.Now(), 42); err == nil {
+		t.Error("There should be an error on an inexistant period")
+	}
+}
+
+func TestCeilCandleTime(t *testing.T) {
+	r, err := CeilCandleTime(time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC), models.W1)
+	if err != nil || !r.Equal(time.Date(2021, 6, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Ceil should be next Monday but is", r, err)
+	}
+
+	monday := time.Date(2021, 6, 14, 0, 0, 0, 0, time.UTC)
+	if r, err := CeilCandleTime(monday, models.W1); err != nil || !r.Equal(monday) {
+		t.Error("Ceil of an open time should be itself but is", r, err)
+	}
+}
+
+func TestStepCandleTime(t *testing.T) {
+	start := time.Date(2021, 6, 10, 12, 34, 0, 0, time.UTC)
+
+	if r, _ := StepCandleTime(start, models.H1, 2); !r.Equal(time.Date(2021, 6, 10, 14, 0, 0, 0, time.UTC)) {
+		t.Error("Step should be 2 hours after the floor but is", r)
+	}
+	if r, _ := StepCandleTime(start, models.H1, -1); !r.Equal(time.Date(2021, 6, 10, 11, 0, 0, 0, time.UTC)) {
+		t.Error("Step should be 1 hour before the floor but is", r)
+	}
+}
+
+func TestIsCandleTime(t *testing.T) {
+	if ok, _ := IsCandleTime(time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC), models.W1); !ok {
+		t.Error("Monday should be a weekly open time")
+	}
+	if ok, _ := IsCandleTime(time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC), models.W1); ok {
+		t.Error("Thursday should not be a weekly open time")
+	}
+}
+
+func TestCountCandles(t *testing.T) {
+	start := time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Start, End time.Time
+		Period     int64
+		Count      int64
+	}{
+		{Start: start, End: start, Period: models.M1, Count: 1},
+		{Start: start, End: start.Add(time.Hour), Period: models.M1, Count: 61},
+		{Start: start.Add(time.Second), End: start.Add(time.Hour - time.Second), Period: models.M1, Count: 59},
+		{Start: start.Add(time.Second), End: start.Add(30 * time.Second), Period: models.M1, Count: 0},
+		{Start: start.Add(time.Hour), End: start, Period: models.M1, Count: 0},
+		{Start: start, End: start.Add(28 * 24 * time.Hour), Period: models.W1, Count: 4},
+	}
+
+	for i, c := range cases {
+		if r, err := CountCandles(c.Start, c.End, c.Period); err != nil || r != c.Count {
+			t.Error("Count of test", i, "should be", c.Count, "but is", r, err)
+		}
+	}
+}
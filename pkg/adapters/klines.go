@@ -0,0 +1,241 @@
+package adapters
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+
+	"github.com/cryptellation/models.go"
+)
+
+// Intervals represents every intervals supported by Binance API
+func Intervals() []int64 {
+	return []int64{
+		models.M1,
+		models.M3,
+		models.M5,
+		models.M15,
+		models.M30,
+		models.H1,
+		models.H2,
+		models.H4,
+		models.H6,
+		models.H8,
+		models.H12,
+		models.D1,
+		models.D3,
+		models.W1,
+	}
+}
+
+// PeriodToInterval converts an interval to its corresponding epoch
+func PeriodToInterval(period int64) (e string, err error) {
+	switch period {
+	case models.M1:
+		return "1m", nil
+	case models.M3:
+		return "3m", nil
+	case models.M5:
+		return "5m", nil
+	case models.M15:
+		return "15m", nil
+	case models.M30:
+		return "30m", nil
+	case models.H1:
+		return "1h", nil
+	case models.H2:
+		return "2h", nil
+	case models.H4:
+		return "4h", nil
+	case models.H6:
+		return "6h", nil
+	case models.H8:
+		return "8h", nil
+	case models.H12:
+		return "12h", nil
+	case models.D1:
+		return "1d", nil
+	case models.D3:
+		return "3d", nil
+	case models.W1:
+		return "1w", nil
+	default:
+		return e, fmt.Errorf("interval error: unknown period")
+	}
+}
+
+// IntervalToPeriod converts a Binance interval (i.e. "15m") to its corresponding period
+func IntervalToPeriod(interval string) (int64, error) {
+	for _, p := range Intervals() {
+		if i, err := PeriodToInterval(p); err == nil && i == interval {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("interval error: unknown interval %q", interval)
+}
+
+// TimeCandleStickToKLine will take the time from a candle and will convert it
+// to Kline time, in milliseconds. Sub-millisecond precision is truncated
+// towards the past, so the kline time is never after the candle time.
+func TimeCandleStickToKLine(t time.Time) int64 {
+	ns := t.UnixNano()
+	ms := ns / int64(time.Millisecond)
+	if ns < 0 && ns%int64(time.Millisecond) != 0 {
+		ms--
+	}
+	return ms
+}
+
+// TimeKLineToCandleStick will take the time from a kline, in milliseconds, and
+// will convert it to candle time, in UTC
+func TimeKLineToCandleStick(t int64) time.Time {
+	return time.Unix(0, t*int64(time.Millisecond)).UTC()
+}
+
+// KLineToCandleStick will convert KLine binance format for CandleStick
+func KLineToCandleStick(k binance.Kline) (models.CandleStick, error) {
+	var c models.CandleStick
+
+	// Convert Open
+	open, err := strconv.ParseFloat(k.Open, 64)
+	if err != nil {
+		return c, err
+	}
+
+	// Convert High
+	high, err := strconv.ParseFloat(k.High, 64)
+	if err != nil {
+		return c, err
+	}
+
+	// Convert Low
+	low, err := strconv.ParseFloat(k.Low, 64)
+	if err != nil {
+		return c, err
+	}
+
+	// Convert Close
+	close, err := strconv.ParseFloat(k.Close, 64)
+	if err != nil {
+		return c, err
+	}
+
+	// Instanciate Candle
+	c = models.CandleStick{
+		Time:  TimeKLineToCandleStick(k.OpenTime),
+		Open:  open,
+		High:  high,
+		Low:   low,
+		Close: close,
+	}
+
+	return c, nil
+}
+
+// KLinesToCandleSticks will transform a slice of binance format for CandleStick
+func KLinesToCandleSticks(kl []*binance.Kline) ([]models.CandleStick, error) {
+	var err error
+
+	cs := make([]models.CandleStick, len(kl))
+	for i, k := range kl {
+		if cs[i], err = KLineToCandleStick(*k); err != nil {
+			return nil, err
+		}
+	}
+
+	return cs, nil
+}
+
+// KLineFields is the minimal number of fields of a kline in Binance format, the
+// last ignored field excepted
+const KLineFields = 11
+
+// CandleStickToKLine will convert a CandleStick to KLine binance format, for
+// the given period. As candlesticks have no volume, volumes are set to 0.
+func CandleStickToKLine(c models.CandleStick, period int64) binance.Kline {
+	openTime := TimeCandleStickToKLine(c.Time)
+	closeTime := TimeCandleStickToKLine(c.Time.Add(time.Duration(period)*time.Second)) - 1
+
+	return binance.Kline{
+		OpenTime:                 openTime,
+		Open:                     FormatFloat(c.Open),
+		High:                     FormatFloat(c.High),
+		Low:                      FormatFloat(c.Low),
+		Close:                    FormatFloat(c.Close),
+		Volume:                   "0",
+		CloseTime:                closeTime,
+		QuoteAssetVolume:         "0",
+		TakerBuyBaseAssetVolume:  "0",
+		TakerBuyQuoteAssetVolume: "0",
+	}
+}
+
+// CandleSticksToKLines will transform a slice of CandleStick to binance format,
+// for the given period
+func CandleSticksToKLines(cs []models.CandleStick, period int64) []*binance.Kline {
+	kl := make([]*binance.Kline, len(cs))
+	for i, c := range cs {
+		k := CandleStickToKLine(c, period)
+		kl[i] = &k
+	}
+	return kl
+}
+
+// KLineToFields will convert a KLine to its fields, in the order of Binance
+// API and data exports
+func KLineToFields(k binance.Kline) []string {
+	return []string{
+		strconv.FormatInt(k.OpenTime, 10),
+		k.Open,
+		k.High,
+		k.Low,
+		k.Close,
+		k.Volume,
+		strconv.FormatInt(k.CloseTime, 10),
+		k.QuoteAssetVolume,
+		strconv.FormatInt(k.TradeNum, 10),
+		k.TakerBuyBaseAssetVolume,
+		k.TakerBuyQuoteAssetVolume,
+		"0",
+	}
+}
+
+// KLineFromFields will create a KLine from its fields, in the order of Binance
+// API and data exports
+func KLineFromFields(fields []string) (binance.Kline, error) {
+	if len(fields) < KLineFields {
+		return binance.Kline{}, fmt.Errorf("kline error: %d fields instead of %d", len(fields), KLineFields)
+	}
+
+	var err error
+	k := binance.Kline{
+		Open:                     fields[1],
+		High:                     fields[2],
+		Low:                      fields[3],
+		Close:                    fields[4],
+		Volume:                   fields[5],
+		QuoteAssetVolume:         fields[7],
+		TakerBuyBaseAssetVolume:  fields[9],
+		TakerBuyQuoteAssetVolume: fields[10],
+	}
+
+	if k.OpenTime, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return binance.Kline{}, err
+	}
+	if k.CloseTime, err = strconv.ParseInt(fields[6], 10, 64); err != nil {
+		return binance.Kline{}, err
+	}
+	if k.TradeNum, err = strconv.ParseInt(fields[8], 10, 64); err != nil {
+		return binance.Kline{}, err
+	}
+
+	return k, nil
+}
+
+// FormatFloat will format the float as Binance does, with the shortest
+// decimal representation and without exponent
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -15,11 +15,11 @@ var testCasesKLineToCandleStick = []struct {
 }{
 	{
 		KLine:       binance.Kline{OpenTime: 0, Open: "1.0", High: "2.0", Low: "0.5", Close: "1.5"},
-		CandleStick: models.CandleStick{Time: time.Unix(0, 0), Open: 1, High: 2, Low: 0.5, Close: 1.5},
+		CandleStick: models.CandleStick{Time: time.Unix(0, 0).UTC(), Open: 1, High: 2, Low: 0.5, Close: 1.5},
 	},
 	{
 		KLine:       binance.Kline{OpenTime: 0, Open: "2.0", High: "4.0", Low: "1", Close: "3"},
-		CandleStick: models.CandleStick{Time: time.Unix(0, 0), Open: 2, High: 4, Low: 1, Close: 3},
+		CandleStick: models.CandleStick{Time: time.Unix(0, 0).UTC(), Open: 2, High: 4, Low: 1, Close: 3},
 	},
 }
 
@@ -114,7 +114,9 @@ var timeKLineToCandleStickTests = []struct {
 	BinanceTimestamp int64
 	Time             time.Time
 }{
-	{BinanceTimestamp: 1257894000000, Time: time.Unix(1257894000, 0)},
+	{BinanceTimestamp: 1257894000000, Time: time.Unix(1257894000, 0).UTC()},
+	{BinanceTimestamp: 1257894000123, Time: time.Unix(1257894000, 123*int64(time.Millisecond)).UTC()},
+	{BinanceTimestamp: -1500, Time: time.Unix(-2, 500*int64(time.Millisecond)).UTC()},
 }
 
 func TestTimeKLineToCandleStick(t *testing.T) {
@@ -135,6 +137,25 @@ func TestTimeCandleStickToKLine(t *testing.T) {
 	}
 }
 
+func TestTimeCandleStickToKLine_SubMillisecond(t *testing.T) {
+	if r := TimeCandleStickToKLine(time.Unix(1257894000, 123456789)); r != 1257894000123 {
+		t.Error("Time should be truncated to 1257894000123 but is", r)
+	}
+	if r := TimeCandleStickToKLine(time.Unix(-1, 999999999)); r != -1 {
+		t.Error("Time should be truncated to -1 but is", r)
+	}
+}
+
+func TestTimeKLineToCandleStick_UTC(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	local := time.Date(2021, 6, 10, 12, 0, 0, 0, loc)
+
+	r := TimeKLineToCandleStick(TimeCandleStickToKLine(local))
+	if r.Location() != time.UTC || !r.Equal(local) {
+		t.Error("Time should be", local.UTC(), "but is", r)
+	}
+}
+
 var possibleIntervals = map[int64]string{
 	models.M1:  "1m",
 	models.M3:  "3m",
@@ -167,3 +188,105 @@ func TestPeriodToInterval_InexistantPeriod(t *testing.T) {
 		t.Error("Period 0 should throw an error")
 	}
 }
+
+func TestIntervalToPeriod(t *testing.T) {
+	for k, v := range possibleIntervals {
+		if p, err := IntervalToPeriod(v); err != nil {
+			t.Error("Period for Interval", v, "should not throw an error:", err)
+		} else if p != k {
+			t.Error("Period for Interval", v, "does not correspond : should be", k, "but is", p)
+		}
+	}
+}
+
+func TestIntervalToPeriod_InexistantInterval(t *testing.T) {
+	if _, err := IntervalToPeriod("2m"); err == nil {
+		t.Error("Interval 2m should throw an error")
+	}
+}
+
+func TestCandleStickToKLine(t *testing.T) {
+	c := models.CandleStick{Time: time.Unix(1257894000, 0).UTC(), Open: 0.00000123, High: 2, Low: 0.5, Close: 1.5}
+
+	k := CandleStickToKLine(c, models.M15)
+	if k.OpenTime != 1257894000000 || k.CloseTime != 1257894899999 {
+		t.Error("Times are not correct:", k.OpenTime, k.CloseTime)
+	}
+	if k.Open != "0.00000123" || k.High != "2" || k.Low != "0.5" || k.Close != "1.5" {
+		t.Error("Values are not correct:", k)
+	}
+
+	// Round trip
+	r, err := KLineToCandleStick(k)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if r != c {
+		t.Error("CandleStick should be", c, "but is", r)
+	}
+}
+
+func TestCandleSticksToKLines(t *testing.T) {
+	cs := []models.CandleStick{
+		{Time: time.Unix(0, 0).UTC(), Open: 1, High: 2, Low: 0.5, Close: 1.5},
+		{Time: time.Unix(60, 0).UTC(), Open: 1.5, High: 3, Low: 1, Close: 2},
+	}
+
+	r, err := KLinesToCandleSticks(CandleSticksToKLines(cs, models.M1))
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	for i := range cs {
+		if r[i] != cs[i] {
+			t.Error("CandleStick", i, "should be", cs[i], "but is", r[i])
+		}
+	}
+}
+
+func TestKLineFields(t *testing.T) {
+	k := binance.Kline{
+		OpenTime: 60000, Open: "1", High: "2", Low: "0.5", Close: "1.5", Volume: "10",
+		CloseTime: 119999, QuoteAssetVolume: "15", TradeNum: 42,
+		TakerBuyBaseAssetVolume: "4", TakerBuyQuoteAssetVolume: "6",
+	}
+
+	fields := KLineToFields(k)
+	if len(fields) != KLineFields+1 {
+		t.Fatal("There should be", KLineFields+1, "fields but there is", len(fields))
+	}
+
+	r, err := KLineFromFields(fields)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if r != k {
+		t.Error("KLine should be", k, "but is", r)
+	}
+}
+
+func TestKLineFromFields_Incorrect(t *testing.T) {
+	if _, err := KLineFromFields([]string{"0", "1"}); err == nil {
+		t.Error("There should be an error on missing fields")
+	}
+
+	fields := KLineToFields(binance.Kline{})
+	fields[0] = "error"
+	if _, err := KLineFromFields(fields); err == nil {
+		t.Error("There should be an error on open time")
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	cases := map[float64]string{
+		0:          "0",
+		1.5:        "1.5",
+		0.00000001: "0.00000001",
+		1e21:       "1000000000000000000000",
+	}
+
+	for f, s := range cases {
+		if r := FormatFloat(f); r != s {
+			t.Error("Float", f, "should be formatted as", s, "but is", r)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package binance
+
+import (
+	"context"
+
+	binance "github.com/adshao/go-binance/v2"
+)
+
+// commissionRateDivider is the divider that converts a Binance commission
+// (expressed in basis points, e.g. 10) into a rate (e.g. 0.001)
+const commissionRateDivider = 10000
+
+// Balance represents the balance of an asset on the account
+type Balance struct {
+	Asset  string
+	Free   float64
+	Locked float64
+}
+
+// BalanceDecimals are the exact amounts of a balance, as sent by Binance
+type BalanceDecimals struct {
+	Free   Decimal
+	Locked Decimal
+}
+
+// ExactBalance is a balance with its exact decimal amounts next to the
+// float64 ones
+type ExactBalance struct {
+	Balance
+	Exact BalanceDecimals
+}
+
+// NewExactBalance will return the balance with the shortest decimal
+// representations of its amounts, for balances that don't come from Binance
+func NewExactBalance(b Balance) ExactBalance {
+	return ExactBalance{
+		Balance: b,
+		Exact: BalanceDecimals{
+			Free:   DecimalFromFloat(b.Free),
+			Locked: DecimalFromFloat(b.Locked),
+		},
+	}
+}
+
+// Total will return the sum of free and locked amounts
+func (b Balance) Total() float64 {
+	return b.Free + b.Locked
+}
+
+// IsZero will return true if there is nothing free nor locked on the balance
+func (b Balance) IsZero() bool {
+	return b.Free == 0 && b.Locked == 0
+}
+
+// Account represents the spot account informations
+type Account struct {
+	MakerCommission  float64
+	TakerCommission  float64
+	BuyerCommission  float64
+	SellerCommission float64
+	CanTrade         bool
+	CanWithdraw      bool
+	CanDeposit       bool
+	Balances         []Balance
+}
+
+// ExactAccount is an account with the exact decimal amounts of its balances
+type ExactAccount struct {
+	Account
+	// ExactBalances are the balances of the account with their exact
+	// amounts, in the same order
+	ExactBalances []ExactBalance
+}
+
+// NewExactAccount will return the account with the shortest decimal
+// representations of its amounts, for accounts that don't come from Binance
+func NewExactAccount(a Account) ExactAccount {
+	ea := ExactAccount{
+		Account:       a,
+		ExactBalances: make([]ExactBalance, len(a.Balances)),
+	}
+	for i, b := range a.Balances {
+		ea.ExactBalances[i] = NewExactBalance(b)
+	}
+	return ea
+}
+
+// Balance will return the balance corresponding to the asset, if it exists
+func (a Account) Balance(asset string) (Balance, bool) {
+	for _, b := range a.Balances {
+		if b.Asset == asset {
+			return b, true
+		}
+	}
+	return Balance{}, false
+}
+
+// AccountService is the real service for account
+type AccountService struct {
+	client       func() *binance.Client
+	options      []binance.RequestOption
+	zeroBalances bool
+	tracer       TracerInterface
+}
+
+// Do will execute a request for account informations
+func (s *AccountService) Do(ctx context.Context) (Account, error) {
+	a, err := s.DoExact(ctx)
+	return a.Account, err
+}
+
+// DoExact will execute a request for account informations and keep the exact
+// decimal amounts of the balances
+func (s *AccountService) DoExact(ctx context.Context) (account ExactAccount, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanAccount)
+	defer func() { span.end(err) }()
+
+	// Get account
+	var a *binance.Account
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		a, err = s.client().NewGetAccountService().Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return ExactAccount{}, err
+	}
+
+	// Change it to right format
+	return exactAccountFromBinance(*a, s.zeroBalances)
+}
+
+// ZeroBalances will specify if the balances with nothing free nor locked
+// should be kept in the next account request
+func (s *AccountService) ZeroBalances(keep bool) AccountServiceInterface {
+	s.zeroBalances = keep
+	return s
+}
+
+func exactBalanceFromBinance(b binance.Balance) (ExactBalance, error) {
+	d, f, err := parseDecimals(b.Free, b.Locked)
+	if err != nil {
+		return ExactBalance{}, err
+	}
+
+	return ExactBalance{
+		Balance: Balance{
+			Asset:  b.Asset,
+			Free:   f[0],
+			Locked: f[1],
+		},
+		Exact: BalanceDecimals{
+			Free:   d[0],
+			Locked: d[1],
+		},
+	}, nil
+}
+
+func exactAccountFromBinance(a binance.Account, zeroBalances bool) (ExactAccount, error) {
+	account := ExactAccount{
+		Account: Account{
+			MakerCommission:  float64(a.MakerCommission) / commissionRateDivider,
+			TakerCommission:  float64(a.TakerCommission) / commissionRateDivider,
+			BuyerCommission:  float64(a.BuyerCommission) / commissionRateDivider,
+			SellerCommission: float64(a.SellerCommission) / commissionRateDivider,
+			CanTrade:         a.CanTrade,
+			CanWithdraw:      a.CanWithdraw,
+			CanDeposit:       a.CanDeposit,
+			Balances:         make([]Balance, 0, len(a.Balances)),
+		},
+		ExactBalances: make([]ExactBalance, 0, len(a.Balances)),
+	}
+
+	for _, rb := range a.Balances {
+		b, err := exactBalanceFromBinance(rb)
+		if err != nil {
+			return ExactAccount{}, err
+		}
+
+		// Filter out empty balances if not specified otherwise
+		if !zeroBalances && b.IsZero() {
+			continue
+		}
+
+		account.Balances = append(account.Balances, b.Balance)
+		account.ExactBalances = append(account.ExactBalances, b)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,103 @@
+package binance
+
+import (
+	"math/big"
+	"testing"
+
+	binance "github.com/adshao/go-binance/v2"
+)
+
+func TestAccountFromBinance(t *testing.T) {
+	ra := binance.Account{
+		MakerCommission: 10,
+		TakerCommission: 15,
+		CanTrade:        true,
+		Balances: []binance.Balance{
+			{Asset: "BTC", Free: "0.00100000", Locked: "0.50000000"},
+			{Asset: "ETH", Free: "0.00000000", Locked: "0.00000000"},
+		},
+	}
+
+	a, err := exactAccountFromBinance(ra, false)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if a.MakerCommission != 0.001 || a.TakerCommission != 0.0015 {
+		t.Error("Commissions are not converted correctly:", a.MakerCommission, a.TakerCommission)
+	}
+
+	if !a.CanTrade || a.CanWithdraw || a.CanDeposit {
+		t.Error("Permissions are not converted correctly")
+	}
+
+	if len(a.Balances) != 1 {
+		t.Fatal("There should be 1 balance, but there is", len(a.Balances))
+	}
+
+	expected := Balance{Asset: "BTC", Free: 0.001, Locked: 0.5}
+	if a.Balances[0] != expected {
+		t.Error("Balance is not converted correctly:", expected, a.Balances[0])
+	}
+}
+
+func TestAccountFromBinance_ZeroBalances(t *testing.T) {
+	ra := binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "BTC", Free: "1", Locked: "0"},
+			{Asset: "ETH", Free: "0", Locked: "0"},
+		},
+	}
+
+	a, err := exactAccountFromBinance(ra, true)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if len(a.Balances) != 2 {
+		t.Fatal("There should be 2 balances, but there is", len(a.Balances))
+	}
+}
+
+func TestAccountFromBinance_IncorrectFree(t *testing.T) {
+	ra := binance.Account{Balances: []binance.Balance{{Asset: "BTC", Free: "error", Locked: "0"}}}
+	if _, err := exactAccountFromBinance(ra, false); err == nil {
+		t.Error("There should be an error on free")
+	}
+}
+
+func TestAccountFromBinance_IncorrectLocked(t *testing.T) {
+	ra := binance.Account{Balances: []binance.Balance{{Asset: "BTC", Free: "0", Locked: "error"}}}
+	if _, err := exactAccountFromBinance(ra, false); err == nil {
+		t.Error("There should be an error on locked")
+	}
+}
+
+func TestAccountFromBinance_Exact(t *testing.T) {
+	ra := binance.Account{
+		Balances: []binance.Balance{
+			{Asset: "BTC", Free: "0.00000001", Locked: "0.30000000"},
+			{Asset: "ETH", Free: "0", Locked: "0"},
+		},
+	}
+
+	a, err := exactAccountFromBinance(ra, false)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if len(a.ExactBalances) != 1 || len(a.Balances) != 1 {
+		t.Fatal("There should be 1 exact balance, but there is", len(a.ExactBalances))
+	}
+
+	b := a.ExactBalances[0]
+	if b.Balance != a.Balances[0] || b.Free != 0.00000001 {
+		t.Error("Exact balance should embed the balance:", b.Balance, a.Balances[0])
+	}
+	if b.Exact.Free.String() != "0.00000001" || b.Exact.Free.Rat().Cmp(big.NewRat(1, 100000000)) != 0 {
+		t.Error("Free amount should be exactly 0.00000001 but is", b.Exact.Free)
+	}
+	if b.Exact.Locked.String() != "0.30000000" {
+		t.Error("Locked amount should be kept as sent but is", b.Exact.Locked)
+	}
+}
@@ -4,57 +4,212 @@ import (
 	"context"
 	"time"
 
-	"github.com/cryptellation/binance.go/internal/adapters"
+	"github.com/cryptellation/binance.go/pkg/adapters"
 
 	binance "github.com/adshao/go-binance/v2"
 	"github.com/cryptellation/models.go"
 )
 
-// CandleStickService is the real service for candlesticks
+const (
+	// DefaultCandleStickLimit is the number of candlesticks that Binance
+	// returns when no limit is specified
+	DefaultCandleStickLimit = 500
+	// MaxCandleStickLimit is the maximum number of candlesticks that Binance can
+	// return in one request, Binance returns an error for higher limits
+	MaxCandleStickLimit = 1000
+)
+
+// CandleStickDecimals are the exact values of a candlestick, as sent by Binance
+type CandleStickDecimals struct {
+	Open   Decimal
+	High   Decimal
+	Low    Decimal
+	Close  Decimal
+	Volume Decimal
+}
+
+// ExactCandleStick is a candlestick with its exact decimal values next to
+// the float64 ones, and its close time
+type ExactCandleStick struct {
+	models.CandleStick
+	Exact CandleStickDecimals
+	// CloseTime is the time of the last millisecond of the candlestick, in UTC
+	CloseTime time.Time
+	// Violations are the validation rules violated by the candlestick, only
+	// set with ValidationPolicyFlag
+	Violations []Violation
+}
+
+// CandleStickService is the real service for candlesticks, it is a builder
+// for a CandleStickRequest
 type CandleStickService struct {
-	service *binance.KlinesService
+	client  *binance.Client
+	request CandleStickRequest
+	metrics MetricsInterface
+	tracer  TracerInterface
 }
 
 // Do will execute a request for candlesticks
 func (s *CandleStickService) Do(ctx context.Context) ([]models.CandleStick, error) {
+	ecs, err := s.DoExact(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := make([]models.CandleStick, len(ecs))
+	for i, c := range ecs {
+		cs[i] = c.CandleStick
+	}
+	return cs, nil
+}
+
+// DoExact will execute a request for candlesticks and keep their exact
+// decimal values and close times
+func (s *CandleStickService) DoExact(ctx context.Context) (cs []ExactCandleStick, err error) {
+	r := s.request
+	interval, _ := adapters.PeriodToInterval(r.Period)
+
+	ctx, span := startSpan(ctx, s.tracer, SpanCandleSticks,
+		Attribute{Key: AttributeSymbol, Value: r.Symbol},
+		Attribute{Key: AttributePeriod, Value: r.Period},
+		Attribute{Key: AttributeInterval, Value: interval},
+		Attribute{Key: AttributeLimit, Value: r.Limit})
+	defer func() { span.end(err) }()
+
 	// Get KLines
-	kl, err := s.service.Do(ctx)
+	var kl []*binance.Kline
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		kl, err = klinesService(s.client, r).Do(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Change them to right format
-	return adapters.KLinesToCandleSticks(kl)
+	_, convert := startSpan(ctx, s.tracer, SpanConvert)
+	cs, err = exactCandleSticksFromKLines(kl)
+	convert.end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	_, validate := startSpan(ctx, s.tracer, SpanValidate)
+	cs, err = r.Validation.Apply(r.Symbol, r.Period, cs)
+	validate.end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	span.setAttributes(Attribute{Key: AttributeResponseSize, Value: len(cs)})
+	if s.metrics != nil {
+		s.metrics.AddCandleSticks(r.Symbol, interval, len(cs))
+	}
+	return cs, nil
+}
+
+// Request will return the request that the service will execute
+func (s *CandleStickService) Request() CandleStickRequest {
+	return s.request
+}
+
+// WithRequest will replace the request that the service will execute
+func (s *CandleStickService) WithRequest(r CandleStickRequest) CandleStickServiceInterface {
+	s.request = r
+	return s
 }
 
 // Symbol will specify a symbol for next candlesticks request
 func (s *CandleStickService) Symbol(symbol string) CandleStickServiceInterface {
-	s.service.Symbol(symbol)
+	s.request = s.request.WithSymbol(symbol)
 	return s
 }
 
 // Period will specify a period for next candlesticks request
 func (s *CandleStickService) Period(period int64) CandleStickServiceInterface {
-	interval, err := adapters.PeriodToInterval(period)
-	if err != nil {
-		interval = "unknown"
-	}
+	s.request = s.request.WithPeriod(period)
+	return s
+}
 
-	s.service.Interval(interval)
+// StartTime will specify the time where the list starts (earliest time) for
+// next candlesticks request
+func (s *CandleStickService) StartTime(startTime time.Time) CandleStickServiceInterface {
+	s.request = s.request.WithStartTime(startTime)
 	return s
 }
 
-// EndTime will specify the time where the list ends (earliest time) for
+// EndTime will specify the time where the list ends (latest time) for
 // next candlesticks request
 func (s *CandleStickService) EndTime(endTime time.Time) CandleStickServiceInterface {
-	binanceTime := adapters.TimeCandleStickToKLine(endTime)
-	s.service.EndTime(binanceTime)
+	s.request = s.request.WithEndTime(endTime)
 	return s
 }
 
 // Limit will specify the number of candlesticks the list should have at its maximum
-// If the limit is higher than the default limit, it will be limited to this one
+// If none is specified, Binance uses DefaultCandleStickLimit
 func (s *CandleStickService) Limit(limit int) CandleStickServiceInterface {
-	s.service.Limit(limit)
+	s.request = s.request.WithLimit(limit)
+	return s
+}
+
+// Validation will specify the policy applied to invalid candlesticks for next
+// candlesticks request. By default, candlesticks are not validated.
+func (s *CandleStickService) Validation(policy ValidationPolicy) CandleStickServiceInterface {
+	s.request = s.request.WithValidation(policy)
 	return s
 }
+
+// klinesService will create a new go-binance service for the request
+func klinesService(client *binance.Client, r CandleStickRequest) *binance.KlinesService {
+	interval, err := adapters.PeriodToInterval(r.Period)
+	if err != nil {
+		interval = "unknown"
+	}
+
+	service := client.NewKlinesService().
+		Symbol(r.Symbol).
+		Interval(interval)
+	if r.Limit != 0 {
+		service.Limit(r.Limit)
+	}
+	if !r.StartTime.IsZero() {
+		service.StartTime(adapters.TimeCandleStickToKLine(r.StartTime))
+	}
+	if !r.EndTime.IsZero() {
+		service.EndTime(adapters.TimeCandleStickToKLine(r.EndTime))
+	}
+
+	return service
+}
+
+func exactCandleSticksFromKLines(kl []*binance.Kline) ([]ExactCandleStick, error) {
+	cs, err := adapters.KLinesToCandleSticks(kl)
+	if err != nil {
+		return nil, err
+	}
+
+	ecs := make([]ExactCandleStick, len(kl))
+	for i, k := range kl {
+		values := []string{k.Open, k.High, k.Low, k.Close, k.Volume}
+		decimals := make([]Decimal, len(values))
+		for j, v := range values {
+			if decimals[j], err = ParseDecimal(v); err != nil {
+				return nil, err
+			}
+		}
+
+		ecs[i] = ExactCandleStick{
+			CandleStick: cs[i],
+			CloseTime:   adapters.TimeKLineToCandleStick(k.CloseTime),
+			Exact: CandleStickDecimals{
+				Open:   decimals[0],
+				High:   decimals[1],
+				Low:    decimals[2],
+				Close:  decimals[3],
+				Volume: decimals[4],
+			},
+		}
+	}
+
+	return ecs, nil
+}
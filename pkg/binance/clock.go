@@ -0,0 +1,119 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTimeSyncInterval is the default interval between two server time
+// synchronizations
+const DefaultTimeSyncInterval = 10 * time.Minute
+
+// ErrTimeSyncInvalidInterval is returned when the time synchronization is
+// started with an interval that is not positive
+var ErrTimeSyncInvalidInterval = errors.New("time sync error: invalid interval")
+
+// clock keeps the offset between the local clock and Binance server clock
+type clock struct {
+	mutex    sync.RWMutex
+	offset   time.Duration
+	latency  time.Duration
+	lastSync time.Time
+}
+
+func (c *clock) set(offset, latency time.Duration, t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.offset = offset
+	c.latency = latency
+	c.lastSync = t
+}
+
+func (c *clock) get() (offset, latency time.Duration, lastSync time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.offset, c.latency, c.lastSync
+}
+
+// SyncTime will measure Binance server time and update the offset applied on
+// signed requests and on the service clock
+func (s *Service) SyncTime(ctx context.Context) error {
+	start := time.Now()
+	serverTime, err := s.client.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+
+	// Consider that the server time has been measured in the middle of the
+	// round-trip
+	latency := end.Sub(start)
+	local := start.Add(latency / 2)
+	offset := timeFromBinance(serverTime).Sub(local)
+
+	s.clock.set(offset, latency, end)
+	return nil
+}
+
+// StartTimeSync will synchronize the time with Binance server and then keep
+// refreshing it at the given interval until the context is done. Only the
+// first synchronization error is returned, next ones keep the previous offset.
+// The interval should be positive.
+func (s *Service) StartTimeSync(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrTimeSyncInvalidInterval
+	}
+
+	if err := s.SyncTime(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = s.SyncTime(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ClockOffset will return the last measured offset between Binance server
+// clock and the local clock (positive if the server is ahead)
+func (s *Service) ClockOffset() time.Duration {
+	offset, _, _ := s.clock.get()
+	return offset
+}
+
+// Latency will return the round-trip latency measured during the last time
+// synchronization
+func (s *Service) Latency() time.Duration {
+	_, latency, _ := s.clock.get()
+	return latency
+}
+
+// LastTimeSync will return the time of the last time synchronization, or zero
+// time if none happened
+func (s *Service) LastTimeSync() time.Time {
+	_, _, lastSync := s.clock.get()
+	return lastSync
+}
+
+// Now will return the current time of Binance server, based on the local
+// clock corrected by the measured offset. It should be used instead of
+// time.Now() for requests relative to the current time (i.e. the end time of
+// the last candlesticks).
+func (s *Service) Now() time.Time {
+	return time.Now().Add(s.ClockOffset())
+}
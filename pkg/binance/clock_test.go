@@ -0,0 +1,165 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+const testClockOffset = 5 * time.Second
+
+// newClockTestServer will create a server that is ahead of the local clock
+// by testClockOffset and will send the received timestamps and candlesticks
+// end times on the channel
+func newClockTestServer(timestamps chan<- int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/time":
+			serverTime := time.Now().Add(testClockOffset).UnixNano() / int64(time.Millisecond)
+			fmt.Fprintf(w, `{"serverTime":%d}`, serverTime)
+		case "/api/v3/account":
+			ts, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			timestamps <- ts
+			fmt.Fprint(w, `{"balances":[]}`)
+		case "/api/v3/klines":
+			ts, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+			timestamps <- ts
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func checkClockOffset(t *testing.T, offset time.Duration) {
+	if diff := offset - testClockOffset; diff > time.Second || diff < -time.Second {
+		t.Error("Offset should be around", testClockOffset, "but is", offset)
+	}
+}
+
+func TestSyncTime(t *testing.T) {
+	timestamps := make(chan int64, 1)
+	server := newClockTestServer(timestamps)
+	defer server.Close()
+
+	s := New("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	if !s.LastTimeSync().IsZero() || s.ClockOffset() != 0 {
+		t.Error("There should be no synchronization before SyncTime")
+	}
+
+	if err := s.SyncTime(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	checkClockOffset(t, s.ClockOffset())
+	checkClockOffset(t, s.Now().Sub(time.Now()))
+	if s.Latency() <= 0 || s.LastTimeSync().IsZero() {
+		t.Error("Latency and last synchronization should be set:", s.Latency(), s.LastTimeSync())
+	}
+
+	// Check that signed requests are corrected
+	if _, err := s.NewAccountService().Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	ts := time.Unix(0, <-timestamps*int64(time.Millisecond))
+	checkClockOffset(t, ts.Sub(time.Now()))
+}
+
+func TestSyncTime_CandleSticksNow(t *testing.T) {
+	timestamps := make(chan int64, 1)
+	server := newClockTestServer(timestamps)
+	defer server.Close()
+
+	var s ServiceInterface = New("key", "secret", WithBaseURL(server.URL))
+	if err := s.(*Service).SyncTime(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	// Check that now-relative candlesticks requests use server time
+	_, err := s.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).EndTime(s.Now()).Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	end := time.Unix(0, <-timestamps*int64(time.Millisecond))
+	checkClockOffset(t, end.Sub(time.Now()))
+}
+
+func TestSyncTime_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := New("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	if err := s.SyncTime(context.TODO()); err == nil {
+		t.Error("There should be an error")
+	}
+
+	if err := s.StartTimeSync(context.TODO(), time.Minute); err == nil {
+		t.Error("There should be an error")
+	}
+}
+
+func TestStartTimeSync_InvalidInterval(t *testing.T) {
+	s := New("key", "secret")
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if err := s.StartTimeSync(context.TODO(), interval); err != ErrTimeSyncInvalidInterval {
+			t.Error("There should be an error with an interval of", interval, "but there is", err)
+		}
+	}
+}
+
+func TestSyncTime_OffsetOnRequest(t *testing.T) {
+	timestamps := make(chan int64, 1)
+	server := newClockTestServer(timestamps)
+	defer server.Close()
+
+	s := New("key", "secret", WithBaseURL(server.URL))
+
+	// Check that a service created before the synchronization uses the new offset
+	account := s.NewAccountService()
+	if err := s.SyncTime(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if _, err := account.Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	ts := time.Unix(0, <-timestamps*int64(time.Millisecond))
+	checkClockOffset(t, ts.Sub(time.Now()))
+}
+
+func TestStartTimeSync(t *testing.T) {
+	server := newClockTestServer(nil)
+	defer server.Close()
+
+	s := New("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := s.StartTimeSync(ctx, 10*time.Millisecond); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	first := s.LastTimeSync()
+	deadline := time.Now().Add(5 * time.Second)
+	for !s.LastTimeSync().After(first) {
+		if time.Now().After(deadline) {
+			t.Fatal("Time should have been synchronized again")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	checkClockOffset(t, s.ClockOffset())
+}
@@ -0,0 +1,114 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/cryptellation/binance.go/pkg/adapters"
+)
+
+// ErrInvalidDecimal is returned when a string is not a valid decimal value
+var ErrInvalidDecimal = errors.New("decimal error: invalid value")
+
+// Decimal is an exact decimal value, kept as the string sent by Binance
+// (i.e. "0.00000123"). It can be converted to a float64 or to an exact
+// rational for computations.
+type Decimal string
+
+// ParseDecimal will check that the string is a valid decimal value, in the
+// format used by Binance (i.e. "-12.345")
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimPrefix(s, "-")
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		digits = digits[:i] + digits[i+1:]
+	}
+
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	return Decimal(s), nil
+}
+
+// parseDecimals will parse the values sent by Binance as decimals, with their
+// float64 counterparts. Empty values are 0.
+func parseDecimals(values ...string) ([]Decimal, []float64, error) {
+	decimals := make([]Decimal, len(values))
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		if v == "" {
+			continue
+		}
+
+		d, err := ParseDecimal(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := d.Float64()
+		if err != nil {
+			return nil, nil, err
+		}
+		decimals[i], floats[i] = d, f
+	}
+
+	return decimals, floats, nil
+}
+
+// DecimalFromFloat will create the decimal corresponding to the shortest
+// representation of the float64
+func DecimalFromFloat(f float64) Decimal {
+	return Decimal(adapters.FormatFloat(f))
+}
+
+// String will return the decimal as sent by Binance
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// Float64 will return the decimal as a float64, that can be inexact
+func (d Decimal) Float64() (float64, error) {
+	if d == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// Rat will return the decimal as an exact rational, or nil if the decimal
+// is not valid. An empty decimal is 0.
+func (d Decimal) Rat() *big.Rat {
+	if d == "" {
+		return new(big.Rat)
+	}
+
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// Cmp will compare exactly the decimal with another one and return -1, 0 or
+// +1 if it is lower, equal or greater. Invalid decimals are lower than any
+// valid one.
+func (d Decimal) Cmp(o Decimal) int {
+	a, b := d.Rat(), o.Rat()
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	default:
+		return a.Cmp(b)
+	}
+}
+
+// Equal will return true if the decimals have the same value, even with a
+// different representation (i.e. "1.10" and "1.1")
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
@@ -0,0 +1,81 @@
+package binance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, s := range []string{"0.00000123", "123456789012345.12345678", "-1.5", "10"} {
+		d, err := ParseDecimal(s)
+		if err != nil {
+			t.Error("There should be no error for", s, "but there is", err)
+		} else if d.String() != s {
+			t.Error("Decimal should be", s, "but is", d)
+		}
+	}
+
+	for _, s := range []string{"", "abc", "1/3", "1e-8", "0x10", ".", "1.2.3", "+1"} {
+		if _, err := ParseDecimal(s); !errors.Is(err, ErrInvalidDecimal) {
+			t.Error("There should be an invalid decimal error for", s, "but there is", err)
+		}
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	if !Decimal("1.10").Equal("1.1") {
+		t.Error("1.10 and 1.1 should be equal")
+	}
+	if Decimal("0.00000123").Cmp("0.00000124") != -1 {
+		t.Error("0.00000123 should be lower than 0.00000124")
+	}
+	a, b := 0.1, 0.2
+	if Decimal("0.3").Cmp(DecimalFromFloat(a+b)) != -1 {
+		t.Error("0.3 should be lower than 0.1+0.2 as float")
+	}
+}
+
+func TestDecimal_Float64(t *testing.T) {
+	f, err := Decimal("0.00000123").Float64()
+	if err != nil || f != 0.00000123 {
+		t.Error("Float should be 0.00000123 but is", f, err)
+	}
+
+	if DecimalFromFloat(0.00000123) != "0.00000123" {
+		t.Error("Decimal should be 0.00000123 but is", DecimalFromFloat(0.00000123))
+	}
+}
+
+func TestExactCandleSticksFromKLines(t *testing.T) {
+	kl := []*binance.Kline{
+		{OpenTime: 60000, CloseTime: 119999, Open: "0.00000123", High: "0.00000130", Low: "0.00000120", Close: "0.00000125", Volume: "123456789012345.12345678"},
+	}
+
+	cs, err := exactCandleSticksFromKLines(kl)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if len(cs) != 1 {
+		t.Fatal("There should be 1 candlestick but there is", len(cs))
+	}
+
+	c := cs[0]
+	if !c.Time.Equal(time.Unix(60, 0)) || !c.CloseTime.Equal(time.Unix(119, 999*int64(time.Millisecond))) {
+		t.Error("Times are not correct:", c.Time, c.CloseTime)
+	}
+	if c.Open != 0.00000123 || c.Close != 0.00000125 {
+		t.Error("Float values are not correct:", c.CandleStick)
+	}
+	if c.Exact.Open != "0.00000123" || c.Exact.High != "0.00000130" || c.Exact.Low != "0.00000120" ||
+		c.Exact.Close != "0.00000125" || c.Exact.Volume != "123456789012345.12345678" {
+		t.Error("Exact values are not correct:", c.Exact)
+	}
+
+	kl[0].Volume = "error"
+	if _, err := exactCandleSticksFromKLines(kl); err == nil {
+		t.Error("There should be an error with an invalid volume")
+	}
+}
@@ -10,13 +10,98 @@ import (
 // Interface is an interface for service
 type ServiceInterface interface {
 	NewCandleStickService() CandleStickServiceInterface
+	NewAccountService() AccountServiceInterface
+	NewCreateOrderService() CreateOrderServiceInterface
+	NewGetOrderService() GetOrderServiceInterface
+	NewCancelOrderService() CancelOrderServiceInterface
+	NewCancelOpenOrdersService() CancelOpenOrdersServiceInterface
+	NewListOpenOrdersService() ListOpenOrdersServiceInterface
+	NewUserDataStreamService() UserDataStreamServiceInterface
+	ClockInterface
+}
+
+// ClockInterface is the interface for the service clock, synchronized with
+// Binance server time
+type ClockInterface interface {
+	SyncTime(ctx context.Context) error
+	StartTimeSync(ctx context.Context, interval time.Duration) error
+	ClockOffset() time.Duration
+	Latency() time.Duration
+	LastTimeSync() time.Time
+	Now() time.Time
 }
 
 // CandleStickServiceInterface is the interface for candle stick services
 type CandleStickServiceInterface interface {
 	Do(ctx context.Context) ([]models.CandleStick, error)
+	DoExact(ctx context.Context) ([]ExactCandleStick, error)
+	Request() CandleStickRequest
+	WithRequest(r CandleStickRequest) CandleStickServiceInterface
 	Symbol(symbol string) CandleStickServiceInterface
 	Period(period int64) CandleStickServiceInterface
+	StartTime(startTime time.Time) CandleStickServiceInterface
 	EndTime(endTime time.Time) CandleStickServiceInterface
 	Limit(limit int) CandleStickServiceInterface
+	Validation(policy ValidationPolicy) CandleStickServiceInterface
+}
+
+// AccountServiceInterface is the interface for account services
+type AccountServiceInterface interface {
+	Do(ctx context.Context) (Account, error)
+	DoExact(ctx context.Context) (ExactAccount, error)
+	ZeroBalances(keep bool) AccountServiceInterface
+}
+
+// CreateOrderServiceInterface is the interface for order creation services
+type CreateOrderServiceInterface interface {
+	Do(ctx context.Context) (Order, error)
+	DoExact(ctx context.Context) (ExactOrder, error)
+	Symbol(symbol string) CreateOrderServiceInterface
+	Side(side OrderSide) CreateOrderServiceInterface
+	Type(orderType OrderType) CreateOrderServiceInterface
+	Quantity(quantity float64) CreateOrderServiceInterface
+	Price(price float64) CreateOrderServiceInterface
+	StopPrice(stopPrice float64) CreateOrderServiceInterface
+	TimeInForce(timeInForce TimeInForce) CreateOrderServiceInterface
+	ClientOrderID(id string) CreateOrderServiceInterface
+}
+
+// GetOrderServiceInterface is the interface for order query services
+type GetOrderServiceInterface interface {
+	Do(ctx context.Context) (Order, error)
+	DoExact(ctx context.Context) (ExactOrder, error)
+	Symbol(symbol string) GetOrderServiceInterface
+	OrderID(id int64) GetOrderServiceInterface
+	ClientOrderID(id string) GetOrderServiceInterface
+}
+
+// CancelOrderServiceInterface is the interface for order cancellation services
+type CancelOrderServiceInterface interface {
+	Do(ctx context.Context) (Order, error)
+	DoExact(ctx context.Context) (ExactOrder, error)
+	Symbol(symbol string) CancelOrderServiceInterface
+	OrderID(id int64) CancelOrderServiceInterface
+	ClientOrderID(id string) CancelOrderServiceInterface
+}
+
+// CancelOpenOrdersServiceInterface is the interface for services cancelling
+// every open orders on a symbol
+type CancelOpenOrdersServiceInterface interface {
+	Do(ctx context.Context) ([]Order, error)
+	DoExact(ctx context.Context) ([]ExactOrder, error)
+	Symbol(symbol string) CancelOpenOrdersServiceInterface
+}
+
+// ListOpenOrdersServiceInterface is the interface for open orders listing services
+type ListOpenOrdersServiceInterface interface {
+	Do(ctx context.Context) ([]Order, error)
+	DoExact(ctx context.Context) ([]ExactOrder, error)
+	Symbol(symbol string) ListOpenOrdersServiceInterface
+}
+
+// UserDataStreamServiceInterface is the interface for user data stream services
+type UserDataStreamServiceInterface interface {
+	Do(ctx context.Context) (<-chan UserDataEvent, error)
+	KeepAlive(interval time.Duration) UserDataStreamServiceInterface
+	ErrorHandler(handler func(error)) UserDataStreamServiceInterface
 }
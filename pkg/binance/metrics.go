@@ -0,0 +1,115 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// usedWeightHeaderPrefix is the prefix of the headers where Binance sends
+	// the request weight used on an interval (i.e. "X-MBX-USED-WEIGHT-1M")
+	usedWeightHeaderPrefix = "X-Mbx-Used-Weight-"
+
+	// userDataStreamName is the name of the user data stream in metrics
+	userDataStreamName = "user_data"
+)
+
+// Request outcomes reported to MetricsInterface
+const (
+	// RequestOutcomeSuccess is the outcome of requests with a 2xx status
+	RequestOutcomeSuccess = "success"
+	// RequestOutcomeClientError is the outcome of requests with a 4xx status,
+	// rate limits excepted
+	RequestOutcomeClientError = "client_error"
+	// RequestOutcomeServerError is the outcome of requests with a 5xx status
+	RequestOutcomeServerError = "server_error"
+	// RequestOutcomeRateLimited is the outcome of requests rejected because of
+	// rate limits (429 and 418 statuses)
+	RequestOutcomeRateLimited = "rate_limited"
+	// RequestOutcomeNetworkError is the outcome of requests without response
+	RequestOutcomeNetworkError = "network_error"
+)
+
+// MetricsInterface is the interface for metrics collectors of the real
+// service. Methods can be called concurrently.
+type MetricsInterface interface {
+	// ObserveRequest is called after each REST request with its endpoint
+	// (i.e. "/api/v3/klines"), outcome and duration
+	ObserveRequest(endpoint, outcome string, duration time.Duration)
+	// SetUsedWeight is called with the request weight used on the interval
+	// (i.e. "1m"), as sent by Binance in responses headers
+	SetUsedWeight(interval string, weight int64)
+	// AddRetry is called each time an operation is retried after an error
+	AddRetry(operation string)
+	// AddStreamReconnect is called each time a stream reconnects
+	AddStreamReconnect(stream string)
+	// AddCandleSticks is called with the number of candlesticks returned by a
+	// candlesticks request
+	AddCandleSticks(symbol, interval string, count int)
+}
+
+// nopMetrics is the metrics collector used when none is set
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveRequest(endpoint, outcome string, duration time.Duration) {}
+func (nopMetrics) SetUsedWeight(interval string, weight int64)                     {}
+func (nopMetrics) AddRetry(operation string)                                       {}
+func (nopMetrics) AddStreamReconnect(stream string)                                {}
+func (nopMetrics) AddCandleSticks(symbol, interval string, count int)              {}
+
+// metricsTransport is a RoundTripper reporting REST requests to metrics
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics MetricsInterface
+}
+
+// RoundTrip will forward the request and report it
+func (t *metricsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	start := time.Now()
+	res, err := next.RoundTrip(r)
+	duration := time.Since(start)
+
+	if err != nil {
+		t.metrics.ObserveRequest(r.URL.Path, RequestOutcomeNetworkError, duration)
+		return res, err
+	}
+
+	t.metrics.ObserveRequest(r.URL.Path, requestOutcome(res.StatusCode), duration)
+	for name, values := range res.Header {
+		if !strings.HasPrefix(name, usedWeightHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+
+		weight, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		t.metrics.SetUsedWeight(strings.ToLower(strings.TrimPrefix(name, usedWeightHeaderPrefix)), weight)
+	}
+
+	return res, nil
+}
+
+func (t *metricsTransport) unwrap() http.RoundTripper {
+	return t.next
+}
+
+func requestOutcome(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusTeapot:
+		return RequestOutcomeRateLimited
+	case status >= 500:
+		return RequestOutcomeServerError
+	case status >= 400:
+		return RequestOutcomeClientError
+	default:
+		return RequestOutcomeSuccess
+	}
+}
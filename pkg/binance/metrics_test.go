@@ -0,0 +1,129 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+// recordingMetrics is a metrics collector recording what it receives
+type recordingMetrics struct {
+	mutex        sync.Mutex
+	requests     []string
+	weights      map[string]int64
+	retries      int
+	reconnects   int
+	candleSticks map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		weights:      make(map[string]int64),
+		candleSticks: make(map[string]int),
+	}
+}
+
+func (m *recordingMetrics) ObserveRequest(endpoint, outcome string, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = append(m.requests, endpoint+" "+outcome)
+}
+
+func (m *recordingMetrics) SetUsedWeight(interval string, weight int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.weights[interval] = weight
+}
+
+func (m *recordingMetrics) AddRetry(operation string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retries++
+}
+
+func (m *recordingMetrics) AddStreamReconnect(stream string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.reconnects++
+}
+
+func (m *recordingMetrics) AddCandleSticks(symbol, interval string, count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.candleSticks[symbol+" "+interval] += count
+}
+
+func TestMetrics_Requests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "42")
+		w.Header().Set("X-MBX-USED-WEIGHT", "42")
+		switch r.URL.Query().Get("symbol") {
+		case "LIMITED":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"code":-1003,"msg":"Too many requests."}`)
+		case "ERROR":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"code":-1000,"msg":"Unknown error."}`)
+		default:
+			fmt.Fprint(w, `[[60000,"1","2","0.5","1.5","10",119999,"15",1,"5","7","0"]]`)
+		}
+	}))
+	defer server.Close()
+
+	m := newRecordingMetrics()
+	s := New("key", "secret", WithBaseURL(server.URL), WithMetrics(m))
+
+	for _, symbol := range []string{"BTCUSDT", "LIMITED", "ERROR"} {
+		_, _ = s.NewCandleStickService().Symbol(symbol).Period(models.M1).Do(context.TODO())
+	}
+
+	expected := []string{
+		"/api/v3/klines success",
+		"/api/v3/klines rate_limited",
+		"/api/v3/klines server_error",
+	}
+	if fmt.Sprint(m.requests) != fmt.Sprint(expected) {
+		t.Error("Requests should be", expected, "but are", m.requests)
+	}
+
+	if len(m.weights) != 1 || m.weights["1m"] != 42 {
+		t.Error("Used weight should be 42 on 1m but is", m.weights)
+	}
+
+	if len(m.candleSticks) != 1 || m.candleSticks["BTCUSDT 1m"] != 1 {
+		t.Error("There should be 1 candlestick for BTCUSDT 1m but there is", m.candleSticks)
+	}
+}
+
+func TestMetrics_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	m := newRecordingMetrics()
+	s := New("key", "secret", WithBaseURL(server.URL), WithMetrics(m))
+
+	if _, err := s.NewCandleStickService().Symbol("BTCUSDT").Do(context.TODO()); err == nil {
+		t.Error("There should be an error")
+	}
+	if len(m.requests) != 1 || m.requests[0] != "/api/v3/klines network_error" {
+		t.Error("There should be a network error but there is", m.requests)
+	}
+}
+
+func TestMetrics_KeepProxy(t *testing.T) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	s := New("key", "secret", WithTransport(transport), WithMetrics(newRecordingMetrics())).(*Service)
+
+	if _, ok := s.client.HTTPClient.Transport.(*metricsTransport); !ok {
+		t.Error("Transport should report metrics")
+	}
+	if s.proxy() == nil {
+		t.Error("Proxy of the wrapped transport should be kept")
+	}
+}
@@ -0,0 +1,152 @@
+package binance
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// testnetBaseURL is the base URL of Binance spot testnet REST API
+	testnetBaseURL = "https://testnet.binance.vision"
+	// testnetWebSocketBaseURL is the base URL of Binance spot testnet websocket streams
+	testnetWebSocketBaseURL = "wss://testnet.binance.vision/ws"
+)
+
+// config is the configuration of the real service
+type config struct {
+	baseURL    string
+	wsBaseURL  string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	recvWindow time.Duration
+	timeout    time.Duration
+	testOrders bool
+	metrics    MetricsInterface
+	tracer     TracerInterface
+}
+
+// Option is an option for the real service creation
+type Option func(c *config)
+
+// WithBaseURL will set the base URL of the REST API (i.e. "https://api.binance.com")
+func WithBaseURL(url string) Option {
+	return func(c *config) {
+		c.baseURL = url
+	}
+}
+
+// WithWebSocketBaseURL will set the base URL of the websocket streams
+// (i.e. "wss://stream.binance.com:9443/ws")
+func WithWebSocketBaseURL(url string) Option {
+	return func(c *config) {
+		c.wsBaseURL = url
+	}
+}
+
+// WithTestnet will set the REST API and websocket streams base URLs to the
+// ones of Binance spot testnet
+func WithTestnet() Option {
+	return func(c *config) {
+		c.baseURL = testnetBaseURL
+		c.wsBaseURL = testnetWebSocketBaseURL
+	}
+}
+
+// WithHTTPClient will set the HTTP client used for REST requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithTransport will set the RoundTripper used by the HTTP client for REST
+// requests (i.e. for a proxy or for recording)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *config) {
+		c.transport = transport
+	}
+}
+
+// WithRecvWindow will set the window, after the request timestamp, during
+// which Binance accepts a signed request
+func WithRecvWindow(window time.Duration) Option {
+	return func(c *config) {
+		c.recvWindow = window
+	}
+}
+
+// WithTimeout will set the default timeout of REST requests
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithMetrics will set the collector of the service metrics: REST requests,
+// used weights, retries, stream reconnections and candlesticks
+func WithMetrics(metrics MetricsInterface) Option {
+	return func(c *config) {
+		c.metrics = metrics
+	}
+}
+
+// WithTracer will set the tracer creating spans around the service calls,
+// their HTTP requests, responses decoding and candlesticks conversion and
+// validation. Spans are children of the span of the calls context, if any.
+func WithTracer(tracer TracerInterface) Option {
+	return func(c *config) {
+		c.tracer = tracer
+	}
+}
+
+// withTestOrders will set the test-order mode, it should only be set through
+// NewWithTestOrders to avoid setting it accidentally
+func withTestOrders() Option {
+	return func(c *config) {
+		c.testOrders = true
+	}
+}
+
+func newConfig(options []Option) config {
+	c := config{
+		wsBaseURL: defaultWebSocketBaseURL,
+	}
+
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	return c
+}
+
+// newHTTPClient will return the HTTP client corresponding to the configuration,
+// or nil if the default one should be kept
+func (c config) newHTTPClient() *http.Client {
+	if c.httpClient == nil && c.transport == nil && c.timeout == 0 && c.metrics == nil && c.tracer == nil {
+		return nil
+	}
+
+	// Copy the client to avoid modifying the one given by the user
+	client := &http.Client{}
+	if c.httpClient != nil {
+		*client = *c.httpClient
+	}
+
+	if c.transport != nil {
+		client.Transport = c.transport
+	}
+
+	if c.timeout != 0 {
+		client.Timeout = c.timeout
+	}
+
+	if c.metrics != nil {
+		client.Transport = &metricsTransport{next: client.Transport, metrics: c.metrics}
+	}
+
+	if c.tracer != nil {
+		client.Transport = &tracingTransport{next: client.Transport, tracer: c.tracer}
+	}
+
+	return client
+}
@@ -0,0 +1,133 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// countingTransport is a RoundTripper counting the requests it forwards
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newOptionsTestServer(queries chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if queries != nil {
+			queries <- r.URL.RawQuery
+		}
+		fmt.Fprint(w, `{"balances":[]}`)
+	}))
+}
+
+func TestNew_DefaultOptions(t *testing.T) {
+	s := New("key", "secret").(*Service)
+
+	if s.client.BaseURL != "https://api.binance.com" {
+		t.Error("Base URL should be the production one but is", s.client.BaseURL)
+	}
+
+	if s.wsBaseURL != defaultWebSocketBaseURL {
+		t.Error("Websocket base URL should be the production one but is", s.wsBaseURL)
+	}
+
+	if s.client.HTTPClient != http.DefaultClient {
+		t.Error("HTTP client should be the default one")
+	}
+}
+
+func TestNew_WithTestnet(t *testing.T) {
+	s := New("key", "secret", WithTestnet()).(*Service)
+
+	if s.client.BaseURL != testnetBaseURL || s.wsBaseURL != testnetWebSocketBaseURL {
+		t.Error("URLs should be the testnet ones but are", s.client.BaseURL, s.wsBaseURL)
+	}
+}
+
+func TestNew_WithBaseURL(t *testing.T) {
+	queries := make(chan string, 1)
+	server := newOptionsTestServer(queries)
+	defer server.Close()
+
+	s := New("key", "secret", WithBaseURL(server.URL), WithWebSocketBaseURL("ws://localhost/ws"))
+	if _, err := s.NewAccountService().Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	<-queries
+
+	if ws := s.(*Service).wsBaseURL; ws != "ws://localhost/ws" {
+		t.Error("Websocket base URL is not set:", ws)
+	}
+}
+
+func TestNew_WithTransport(t *testing.T) {
+	server := newOptionsTestServer(nil)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	s := New("key", "secret", WithBaseURL(server.URL), WithTransport(transport))
+	if _, err := s.NewAccountService().Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if transport.count != 1 {
+		t.Error("Request should have gone through transport")
+	}
+}
+
+func TestNew_WithHTTPClient(t *testing.T) {
+	server := newOptionsTestServer(nil)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+	s := New("key", "secret", WithBaseURL(server.URL), WithHTTPClient(client), WithTimeout(time.Minute))
+	if _, err := s.NewAccountService().Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if transport.count != 1 {
+		t.Error("Request should have gone through HTTP client")
+	}
+
+	if client.Timeout != 0 {
+		t.Error("Given HTTP client should not be modified")
+	}
+}
+
+func TestNew_WithRecvWindow(t *testing.T) {
+	queries := make(chan string, 1)
+	server := newOptionsTestServer(queries)
+	defer server.Close()
+
+	s := New("key", "secret", WithBaseURL(server.URL), WithRecvWindow(2*time.Second))
+	if _, err := s.NewAccountService().Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if q, _ := url.ParseQuery(<-queries); q.Get("recvWindow") != "2000" {
+		t.Error("Request should have a recvWindow of 2000:", q)
+	}
+}
+
+func TestNew_WithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, `{"balances":[]}`)
+	}))
+	defer server.Close()
+
+	s := New("key", "secret", WithBaseURL(server.URL), WithTimeout(10*time.Millisecond))
+	if _, err := s.NewAccountService().Do(context.TODO()); err == nil {
+		t.Error("There should be a timeout error")
+	}
+}
@@ -0,0 +1,741 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+	"github.com/cryptellation/binance.go/pkg/adapters"
+)
+
+// OrderSide is the side of an order
+type OrderSide string
+
+const (
+	// OrderSideBuy is the side for buying orders
+	OrderSideBuy OrderSide = "BUY"
+	// OrderSideSell is the side for selling orders
+	OrderSideSell OrderSide = "SELL"
+)
+
+// OrderType is the type of an order
+type OrderType string
+
+const (
+	// OrderTypeMarket is the type for market orders
+	OrderTypeMarket OrderType = "MARKET"
+	// OrderTypeLimit is the type for limit orders
+	OrderTypeLimit OrderType = "LIMIT"
+	// OrderTypeStopLossLimit is the type for stop-loss limit orders
+	OrderTypeStopLossLimit OrderType = "STOP_LOSS_LIMIT"
+	// OrderTypeTakeProfitLimit is the type for take-profit limit orders
+	OrderTypeTakeProfitLimit OrderType = "TAKE_PROFIT_LIMIT"
+)
+
+// OrderStatus is the status of an order
+type OrderStatus string
+
+const (
+	// OrderStatusNew is the status of an order accepted by the engine
+	OrderStatusNew OrderStatus = "NEW"
+	// OrderStatusPartiallyFilled is the status of an order partially filled
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	// OrderStatusFilled is the status of an order completely filled
+	OrderStatusFilled OrderStatus = "FILLED"
+	// OrderStatusCanceled is the status of an order canceled by the user
+	OrderStatusCanceled OrderStatus = "CANCELED"
+	// OrderStatusPendingCancel is the status of an order being canceled
+	OrderStatusPendingCancel OrderStatus = "PENDING_CANCEL"
+	// OrderStatusRejected is the status of an order rejected by the engine
+	OrderStatusRejected OrderStatus = "REJECTED"
+	// OrderStatusExpired is the status of an order that has expired
+	OrderStatusExpired OrderStatus = "EXPIRED"
+)
+
+// IsOpen will return true if the order can still be executed
+func (s OrderStatus) IsOpen() bool {
+	return s == OrderStatusNew || s == OrderStatusPartiallyFilled
+}
+
+// TimeInForce is the time during which an order will be active
+type TimeInForce string
+
+const (
+	// TimeInForceGTC is the time in force for orders that are good till canceled
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC is the time in force for orders that are immediate or cancel
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK is the time in force for orders that are fill or kill
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+var (
+	// ErrOrderNoSymbol is returned when an order has no symbol
+	ErrOrderNoSymbol = errors.New("order error: no symbol")
+	// ErrOrderInvalidSide is returned when an order has an unknown side
+	ErrOrderInvalidSide = errors.New("order error: invalid side")
+	// ErrOrderInvalidType is returned when an order has an unknown type
+	ErrOrderInvalidType = errors.New("order error: invalid type")
+	// ErrOrderInvalidQuantity is returned when an order has a non positive quantity
+	ErrOrderInvalidQuantity = errors.New("order error: invalid quantity")
+	// ErrOrderInvalidPrice is returned when an order has a price incompatible with its type
+	ErrOrderInvalidPrice = errors.New("order error: invalid price")
+	// ErrOrderInvalidStopPrice is returned when an order has a stop price incompatible with its type
+	ErrOrderInvalidStopPrice = errors.New("order error: invalid stop price")
+	// ErrOrderInvalidTimeInForce is returned when an order has a time in force incompatible with its type
+	ErrOrderInvalidTimeInForce = errors.New("order error: invalid time in force")
+	// ErrOrderNoIdentifier is returned when an order is requested without order ID nor client order ID
+	ErrOrderNoIdentifier = errors.New("order error: no order ID nor client order ID")
+)
+
+// OrderRequest represents the parameters of a new order
+type OrderRequest struct {
+	Symbol        string
+	Side          OrderSide
+	Type          OrderType
+	Quantity      float64
+	Price         float64
+	StopPrice     float64
+	TimeInForce   TimeInForce
+	ClientOrderID string
+}
+
+// Validate will check that the order request is consistent with its type,
+// without modifying it
+func (r OrderRequest) Validate() error {
+	if r.Symbol == "" {
+		return ErrOrderNoSymbol
+	}
+
+	if r.Side != OrderSideBuy && r.Side != OrderSideSell {
+		return ErrOrderInvalidSide
+	}
+
+	if r.Quantity <= 0 {
+		return ErrOrderInvalidQuantity
+	}
+
+	switch r.Type {
+	case OrderTypeMarket:
+		if r.Price != 0 {
+			return ErrOrderInvalidPrice
+		}
+		if r.StopPrice != 0 {
+			return ErrOrderInvalidStopPrice
+		}
+		if r.TimeInForce != "" {
+			return ErrOrderInvalidTimeInForce
+		}
+		return nil
+	case OrderTypeLimit:
+		if r.StopPrice != 0 {
+			return ErrOrderInvalidStopPrice
+		}
+	case OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		if r.StopPrice <= 0 {
+			return ErrOrderInvalidStopPrice
+		}
+	default:
+		return ErrOrderInvalidType
+	}
+
+	// Limit orders checks
+	if r.Price <= 0 {
+		return ErrOrderInvalidPrice
+	}
+
+	switch r.TimeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+	default:
+		return ErrOrderInvalidTimeInForce
+	}
+
+	return nil
+}
+
+// WithDefaults will return a copy of the request with the default values sent
+// to Binance for unspecified fields: limit orders are good till canceled if
+// no time in force is specified
+func (r OrderRequest) WithDefaults() OrderRequest {
+	if r.Type != OrderTypeMarket && r.TimeInForce == "" {
+		r.TimeInForce = TimeInForceGTC
+	}
+	return r
+}
+
+// Order represents an order on the exchange
+type Order struct {
+	Symbol                  string
+	ID                      int64
+	ClientOrderID           string
+	Side                    OrderSide
+	Type                    OrderType
+	Status                  OrderStatus
+	TimeInForce             TimeInForce
+	Price                   float64
+	StopPrice               float64
+	Quantity                float64
+	ExecutedQuantity        float64
+	CumulativeQuoteQuantity float64
+	Time                    time.Time
+	UpdateTime              time.Time
+}
+
+// OrderDecimals are the exact values of an order, as sent by Binance
+type OrderDecimals struct {
+	Price                   Decimal
+	StopPrice               Decimal
+	Quantity                Decimal
+	ExecutedQuantity        Decimal
+	CumulativeQuoteQuantity Decimal
+}
+
+// ExactOrder is an order with its exact decimal values next to the float64
+// ones
+type ExactOrder struct {
+	Order
+	Exact OrderDecimals
+}
+
+// NewExactOrder will return the order with the shortest decimal
+// representations of its values, for orders that don't come from Binance
+func NewExactOrder(o Order) ExactOrder {
+	return ExactOrder{
+		Order: o,
+		Exact: OrderDecimals{
+			Price:                   DecimalFromFloat(o.Price),
+			StopPrice:               DecimalFromFloat(o.StopPrice),
+			Quantity:                DecimalFromFloat(o.Quantity),
+			ExecutedQuantity:        DecimalFromFloat(o.ExecutedQuantity),
+			CumulativeQuoteQuantity: DecimalFromFloat(o.CumulativeQuoteQuantity),
+		},
+	}
+}
+
+// ordersFromExact will return the orders without their exact values
+func ordersFromExact(eo []ExactOrder) []Order {
+	if eo == nil {
+		return nil
+	}
+
+	orders := make([]Order, len(eo))
+	for i, o := range eo {
+		orders[i] = o.Order
+	}
+	return orders
+}
+
+// CreateOrderService is the real service for order creation
+type CreateOrderService struct {
+	client  func() *binance.Client
+	now     func() time.Time
+	options []binance.RequestOption
+	request OrderRequest
+	test    bool
+	tracer  TracerInterface
+}
+
+// Do will execute a request for order creation
+func (s *CreateOrderService) Do(ctx context.Context) (Order, error) {
+	o, err := s.DoExact(ctx)
+	return o.Order, err
+}
+
+// DoExact will execute a request for order creation and keep the exact
+// decimal values of the order
+func (s *CreateOrderService) DoExact(ctx context.Context) (o ExactOrder, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanCreateOrder,
+		Attribute{Key: AttributeSymbol, Value: s.request.Symbol})
+	defer func() { span.end(err) }()
+
+	if err := s.request.Validate(); err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Set request on a new service, as optional parameters can't be unset
+	r := s.request.WithDefaults()
+	service := s.client().NewCreateOrderService()
+	setCreateOrderService(service, r)
+
+	// Only validate order if in test mode
+	if s.test {
+		err := call(ctx, s.tracer, func(ctx context.Context) error {
+			return service.Test(ctx, s.options...)
+		})
+		if err != nil {
+			return ExactOrder{}, err
+		}
+		return NewExactOrder(testOrderAcknowledgement(r, s.now())), nil
+	}
+
+	// Create order
+	var res *binance.CreateOrderResponse
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		res, err = service.Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Change it to right format
+	return createOrderResponseToExactOrder(*res, r)
+}
+
+// Symbol will specify a symbol for next order creation
+func (s *CreateOrderService) Symbol(symbol string) CreateOrderServiceInterface {
+	s.request.Symbol = symbol
+	return s
+}
+
+// Side will specify a side for next order creation
+func (s *CreateOrderService) Side(side OrderSide) CreateOrderServiceInterface {
+	s.request.Side = side
+	return s
+}
+
+// Type will specify a type for next order creation
+func (s *CreateOrderService) Type(orderType OrderType) CreateOrderServiceInterface {
+	s.request.Type = orderType
+	return s
+}
+
+// Quantity will specify a base asset quantity for next order creation
+func (s *CreateOrderService) Quantity(quantity float64) CreateOrderServiceInterface {
+	s.request.Quantity = quantity
+	return s
+}
+
+// Price will specify a limit price for next order creation
+func (s *CreateOrderService) Price(price float64) CreateOrderServiceInterface {
+	s.request.Price = price
+	return s
+}
+
+// StopPrice will specify a stop price for next order creation
+func (s *CreateOrderService) StopPrice(stopPrice float64) CreateOrderServiceInterface {
+	s.request.StopPrice = stopPrice
+	return s
+}
+
+// TimeInForce will specify a time in force for next order creation
+func (s *CreateOrderService) TimeInForce(timeInForce TimeInForce) CreateOrderServiceInterface {
+	s.request.TimeInForce = timeInForce
+	return s
+}
+
+// ClientOrderID will specify a custom ID for next order creation
+func (s *CreateOrderService) ClientOrderID(id string) CreateOrderServiceInterface {
+	s.request.ClientOrderID = id
+	return s
+}
+
+// GetOrderService is the real service for order query
+type GetOrderService struct {
+	client        func() *binance.Client
+	options       []binance.RequestOption
+	symbol        string
+	id            int64
+	clientOrderID string
+	tracer        TracerInterface
+}
+
+// Do will execute a request for an order
+func (s *GetOrderService) Do(ctx context.Context) (Order, error) {
+	o, err := s.DoExact(ctx)
+	return o.Order, err
+}
+
+// DoExact will execute a request for an order and keep its exact decimal
+// values
+func (s *GetOrderService) DoExact(ctx context.Context) (order ExactOrder, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanGetOrder,
+		Attribute{Key: AttributeSymbol, Value: s.symbol})
+	defer func() { span.end(err) }()
+
+	if err := CheckOrderIdentifiers(s.symbol, s.id, s.clientOrderID); err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Set request
+	service := s.client().NewGetOrderService().Symbol(s.symbol)
+	if s.id != 0 {
+		service.OrderID(s.id)
+	}
+	if s.clientOrderID != "" {
+		service.OrigClientOrderID(s.clientOrderID)
+	}
+
+	// Get order
+	var o *binance.Order
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		o, err = service.Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Change it to right format
+	return exactOrderFromBinance(*o)
+}
+
+// Symbol will specify the symbol of the requested order
+func (s *GetOrderService) Symbol(symbol string) GetOrderServiceInterface {
+	s.symbol = symbol
+	return s
+}
+
+// OrderID will specify the ID of the requested order
+func (s *GetOrderService) OrderID(id int64) GetOrderServiceInterface {
+	s.id = id
+	return s
+}
+
+// ClientOrderID will specify the client ID of the requested order
+func (s *GetOrderService) ClientOrderID(id string) GetOrderServiceInterface {
+	s.clientOrderID = id
+	return s
+}
+
+// CancelOrderService is the real service for order cancellation
+type CancelOrderService struct {
+	client        func() *binance.Client
+	options       []binance.RequestOption
+	symbol        string
+	id            int64
+	clientOrderID string
+	tracer        TracerInterface
+}
+
+// Do will execute a request for order cancellation
+func (s *CancelOrderService) Do(ctx context.Context) (Order, error) {
+	o, err := s.DoExact(ctx)
+	return o.Order, err
+}
+
+// DoExact will execute a request for order cancellation and keep the exact
+// decimal values of the canceled order
+func (s *CancelOrderService) DoExact(ctx context.Context) (order ExactOrder, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanCancelOrder,
+		Attribute{Key: AttributeSymbol, Value: s.symbol})
+	defer func() { span.end(err) }()
+
+	if err := CheckOrderIdentifiers(s.symbol, s.id, s.clientOrderID); err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Set request
+	service := s.client().NewCancelOrderService().Symbol(s.symbol)
+	if s.id != 0 {
+		service.OrderID(s.id)
+	}
+	if s.clientOrderID != "" {
+		service.OrigClientOrderID(s.clientOrderID)
+	}
+
+	// Cancel order
+	var res *binance.CancelOrderResponse
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		res, err = service.Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	// Change it to right format
+	return cancelOrderResponseToExactOrder(*res)
+}
+
+// Symbol will specify the symbol of the order to cancel
+func (s *CancelOrderService) Symbol(symbol string) CancelOrderServiceInterface {
+	s.symbol = symbol
+	return s
+}
+
+// OrderID will specify the ID of the order to cancel
+func (s *CancelOrderService) OrderID(id int64) CancelOrderServiceInterface {
+	s.id = id
+	return s
+}
+
+// ClientOrderID will specify the client ID of the order to cancel
+func (s *CancelOrderService) ClientOrderID(id string) CancelOrderServiceInterface {
+	s.clientOrderID = id
+	return s
+}
+
+// CancelOpenOrdersService is the real service for cancellation of every open
+// orders on a symbol
+type CancelOpenOrdersService struct {
+	client  func() *binance.Client
+	options []binance.RequestOption
+	symbol  string
+	tracer  TracerInterface
+}
+
+// Do will execute a request for open orders cancellation
+func (s *CancelOpenOrdersService) Do(ctx context.Context) ([]Order, error) {
+	orders, err := s.DoExact(ctx)
+	return ordersFromExact(orders), err
+}
+
+// DoExact will execute a request for open orders cancellation and keep the
+// exact decimal values of the canceled orders
+func (s *CancelOpenOrdersService) DoExact(ctx context.Context) (orders []ExactOrder, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanCancelOpenOrders,
+		Attribute{Key: AttributeSymbol, Value: s.symbol})
+	defer func() { span.end(err) }()
+
+	if s.symbol == "" {
+		return nil, ErrOrderNoSymbol
+	}
+
+	// Cancel orders
+	var res *binance.CancelOpenOrdersResponse
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		res, err = s.client().NewCancelOpenOrdersService().Symbol(s.symbol).Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Change them to right format
+	orders = make([]ExactOrder, len(res.Orders))
+	for i, r := range res.Orders {
+		if orders[i], err = cancelOrderResponseToExactOrder(*r); err != nil {
+			return nil, err
+		}
+	}
+
+	span.setAttributes(Attribute{Key: AttributeResponseSize, Value: len(orders)})
+	return orders, nil
+}
+
+// Symbol will specify the symbol of the orders to cancel
+func (s *CancelOpenOrdersService) Symbol(symbol string) CancelOpenOrdersServiceInterface {
+	s.symbol = symbol
+	return s
+}
+
+// ListOpenOrdersService is the real service for open orders listing
+type ListOpenOrdersService struct {
+	client  func() *binance.Client
+	options []binance.RequestOption
+	symbol  string
+	tracer  TracerInterface
+}
+
+// Do will execute a request for open orders
+func (s *ListOpenOrdersService) Do(ctx context.Context) ([]Order, error) {
+	orders, err := s.DoExact(ctx)
+	return ordersFromExact(orders), err
+}
+
+// DoExact will execute a request for open orders and keep their exact
+// decimal values
+func (s *ListOpenOrdersService) DoExact(ctx context.Context) (orders []ExactOrder, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanListOpenOrders,
+		Attribute{Key: AttributeSymbol, Value: s.symbol})
+	defer func() { span.end(err) }()
+
+	// Set request
+	service := s.client().NewListOpenOrdersService()
+	if s.symbol != "" {
+		service.Symbol(s.symbol)
+	}
+
+	// Get orders
+	var res []*binance.Order
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		res, err = service.Do(ctx, s.options...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Change them to right format
+	orders = make([]ExactOrder, len(res))
+	for i, o := range res {
+		if orders[i], err = exactOrderFromBinance(*o); err != nil {
+			return nil, err
+		}
+	}
+
+	span.setAttributes(Attribute{Key: AttributeResponseSize, Value: len(orders)})
+	return orders, nil
+}
+
+// Symbol will specify the symbol of the open orders, if none is specified then
+// open orders from every symbols will be listed
+func (s *ListOpenOrdersService) Symbol(symbol string) ListOpenOrdersServiceInterface {
+	s.symbol = symbol
+	return s
+}
+
+// CheckOrderIdentifiers will return the error of an order query or
+// cancellation without symbol, or without order ID nor client order ID
+func CheckOrderIdentifiers(symbol string, id int64, clientOrderID string) error {
+	if symbol == "" {
+		return ErrOrderNoSymbol
+	}
+
+	if id == 0 && clientOrderID == "" {
+		return ErrOrderNoIdentifier
+	}
+
+	return nil
+}
+
+func timeFromBinance(t int64) time.Time {
+	return time.Unix(0, t*int64(time.Millisecond)).UTC()
+}
+
+func setCreateOrderService(s *binance.CreateOrderService, r OrderRequest) {
+	s.Symbol(r.Symbol).
+		Side(binance.SideType(r.Side)).
+		Type(binance.OrderType(r.Type)).
+		Quantity(adapters.FormatFloat(r.Quantity)).
+		NewOrderRespType(binance.NewOrderRespTypeRESULT)
+
+	if r.Price != 0 {
+		s.Price(adapters.FormatFloat(r.Price))
+	}
+	if r.StopPrice != 0 {
+		s.StopPrice(adapters.FormatFloat(r.StopPrice))
+	}
+	if r.TimeInForce != "" {
+		s.TimeInForce(binance.TimeInForceType(r.TimeInForce))
+	}
+	if r.ClientOrderID != "" {
+		s.NewClientOrderID(r.ClientOrderID)
+	}
+}
+
+// testOrderAcknowledgement will create the synthetic order returned when an
+// order has been accepted by the test endpoint. As no order has been created,
+// its ID is always 0.
+func testOrderAcknowledgement(r OrderRequest, t time.Time) Order {
+	clientOrderID := r.ClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("test-%d", t.UnixNano())
+	}
+
+	return Order{
+		Symbol:        r.Symbol,
+		ClientOrderID: clientOrderID,
+		Side:          r.Side,
+		Type:          r.Type,
+		Status:        OrderStatusNew,
+		TimeInForce:   r.TimeInForce,
+		Price:         r.Price,
+		StopPrice:     r.StopPrice,
+		Quantity:      r.Quantity,
+		Time:          t,
+		UpdateTime:    t,
+	}
+}
+
+func exactOrderFromBinance(o binance.Order) (ExactOrder, error) {
+	d, f, err := parseDecimals(o.Price, o.StopPrice, o.OrigQuantity, o.ExecutedQuantity, o.CummulativeQuoteQuantity)
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	return ExactOrder{
+		Order: Order{
+			Symbol:                  o.Symbol,
+			ID:                      o.OrderID,
+			ClientOrderID:           o.ClientOrderID,
+			Side:                    OrderSide(o.Side),
+			Type:                    OrderType(o.Type),
+			Status:                  OrderStatus(o.Status),
+			TimeInForce:             TimeInForce(o.TimeInForce),
+			Price:                   f[0],
+			StopPrice:               f[1],
+			Quantity:                f[2],
+			ExecutedQuantity:        f[3],
+			CumulativeQuoteQuantity: f[4],
+			Time:                    timeFromBinance(o.Time),
+			UpdateTime:              timeFromBinance(o.UpdateTime),
+		},
+		Exact: OrderDecimals{
+			Price:                   d[0],
+			StopPrice:               d[1],
+			Quantity:                d[2],
+			ExecutedQuantity:        d[3],
+			CumulativeQuoteQuantity: d[4],
+		},
+	}, nil
+}
+
+// createOrderResponseToExactOrder will convert the order creation response,
+// that has no stop price, with the stop price of the request
+func createOrderResponseToExactOrder(res binance.CreateOrderResponse, r OrderRequest) (ExactOrder, error) {
+	d, f, err := parseDecimals(res.Price, res.OrigQuantity, res.ExecutedQuantity, res.CummulativeQuoteQuantity)
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	t := timeFromBinance(res.TransactTime)
+	return ExactOrder{
+		Order: Order{
+			Symbol:                  res.Symbol,
+			ID:                      res.OrderID,
+			ClientOrderID:           res.ClientOrderID,
+			Side:                    OrderSide(res.Side),
+			Type:                    OrderType(res.Type),
+			Status:                  OrderStatus(res.Status),
+			TimeInForce:             TimeInForce(res.TimeInForce),
+			Price:                   f[0],
+			StopPrice:               r.StopPrice,
+			Quantity:                f[1],
+			ExecutedQuantity:        f[2],
+			CumulativeQuoteQuantity: f[3],
+			Time:                    t,
+			UpdateTime:              t,
+		},
+		Exact: OrderDecimals{
+			Price:                   d[0],
+			StopPrice:               DecimalFromFloat(r.StopPrice),
+			Quantity:                d[1],
+			ExecutedQuantity:        d[2],
+			CumulativeQuoteQuantity: d[3],
+		},
+	}, nil
+}
+
+func cancelOrderResponseToExactOrder(res binance.CancelOrderResponse) (ExactOrder, error) {
+	d, f, err := parseDecimals(res.Price, res.OrigQuantity, res.ExecutedQuantity, res.CummulativeQuoteQuantity)
+	if err != nil {
+		return ExactOrder{}, err
+	}
+
+	return ExactOrder{
+		Order: Order{
+			Symbol:                  res.Symbol,
+			ID:                      res.OrderID,
+			ClientOrderID:           res.OrigClientOrderID,
+			Side:                    OrderSide(res.Side),
+			Type:                    OrderType(res.Type),
+			Status:                  OrderStatus(res.Status),
+			TimeInForce:             TimeInForce(res.TimeInForce),
+			Price:                   f[0],
+			Quantity:                f[1],
+			ExecutedQuantity:        f[2],
+			CumulativeQuoteQuantity: f[3],
+			UpdateTime:              timeFromBinance(res.TransactTime),
+		},
+		Exact: OrderDecimals{
+			Price:                   d[0],
+			Quantity:                d[1],
+			ExecutedQuantity:        d[2],
+			CumulativeQuoteQuantity: d[3],
+		},
+	}, nil
+}
@@ -0,0 +1,343 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+)
+
+var testCasesOrderRequestValidate = []struct {
+	Request OrderRequest
+	Err     error
+}{
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1},
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeLimit, Quantity: 1, Price: 10},
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeStopLossLimit, Quantity: 1, Price: 10, StopPrice: 11},
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideSell, Type: OrderTypeTakeProfitLimit, Quantity: 1, Price: 10, StopPrice: 9, TimeInForce: TimeInForceIOC},
+	},
+	{
+		Request: OrderRequest{Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1},
+		Err:     ErrOrderNoSymbol,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: "error", Type: OrderTypeMarket, Quantity: 1},
+		Err:     ErrOrderInvalidSide,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: "error", Quantity: 1},
+		Err:     ErrOrderInvalidType,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 0},
+		Err:     ErrOrderInvalidQuantity,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1, Price: 10},
+		Err:     ErrOrderInvalidPrice,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1, StopPrice: 10},
+		Err:     ErrOrderInvalidStopPrice,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1, TimeInForce: TimeInForceGTC},
+		Err:     ErrOrderInvalidTimeInForce,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1},
+		Err:     ErrOrderInvalidPrice,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 10, StopPrice: 10},
+		Err:     ErrOrderInvalidStopPrice,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 10, TimeInForce: "error"},
+		Err:     ErrOrderInvalidTimeInForce,
+	},
+	{
+		Request: OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeStopLossLimit, Quantity: 1, Price: 10},
+		Err:     ErrOrderInvalidStopPrice,
+	},
+}
+
+func TestOrderRequestValidate(t *testing.T) {
+	for i, test := range testCasesOrderRequestValidate {
+		if err := test.Request.Validate(); err != test.Err {
+			t.Error("Validation", i, "should return", test.Err, "but returned", err)
+		}
+	}
+}
+
+func TestOrderRequestValidate_NoSideEffect(t *testing.T) {
+	r := OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 10}
+	if err := r.Validate(); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if r.TimeInForce != "" {
+		t.Error("Time in force should not be set by validation but is", r.TimeInForce)
+	}
+}
+
+func TestOrderRequestWithDefaults(t *testing.T) {
+	r := OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 10}
+	if d := r.WithDefaults(); d.TimeInForce != TimeInForceGTC {
+		t.Error("Time in force should be", TimeInForceGTC, "but is", d.TimeInForce)
+	}
+	if r.TimeInForce != "" {
+		t.Error("The original request should not be modified")
+	}
+
+	r = OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 1}
+	if d := r.WithDefaults(); d.TimeInForce != "" {
+		t.Error("Market orders should have no time in force but there is", d.TimeInForce)
+	}
+}
+
+func TestOrderFromBinance(t *testing.T) {
+	o, err := exactOrderFromBinance(binance.Order{
+		Symbol:                   "BTCUSDT",
+		OrderID:                  42,
+		ClientOrderID:            "client",
+		Price:                    "10.5",
+		OrigQuantity:             "2",
+		ExecutedQuantity:         "1",
+		CummulativeQuoteQuantity: "10.5",
+		Status:                   binance.OrderStatusTypePartiallyFilled,
+		TimeInForce:              binance.TimeInForceTypeGTC,
+		Type:                     binance.OrderTypeStopLossLimit,
+		Side:                     binance.SideTypeSell,
+		StopPrice:                "11",
+		Time:                     1257894000000,
+		UpdateTime:               1257894000500,
+	})
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	expected := Order{
+		Symbol:                  "BTCUSDT",
+		ID:                      42,
+		ClientOrderID:           "client",
+		Side:                    OrderSideSell,
+		Type:                    OrderTypeStopLossLimit,
+		Status:                  OrderStatusPartiallyFilled,
+		TimeInForce:             TimeInForceGTC,
+		Price:                   10.5,
+		StopPrice:               11,
+		Quantity:                2,
+		ExecutedQuantity:        1,
+		CumulativeQuoteQuantity: 10.5,
+		Time:                    time.Unix(1257894000, 0).UTC(),
+		UpdateTime:              time.Unix(1257894000, 500*int64(time.Millisecond)).UTC(),
+	}
+
+	if o.Order != expected {
+		t.Error("Order is not converted correctly:", expected, o)
+	}
+}
+
+func TestOrderFromBinance_Exact(t *testing.T) {
+	o, err := exactOrderFromBinance(binance.Order{
+		Symbol:                   "SHIBUSDT",
+		Price:                    "0.00000001",
+		StopPrice:                "0.00000000",
+		OrigQuantity:             "123456789.12345678",
+		ExecutedQuantity:         "0.00000001",
+		CummulativeQuoteQuantity: "0.00000000",
+	})
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	expected := OrderDecimals{
+		Price:                   "0.00000001",
+		StopPrice:               "0.00000000",
+		Quantity:                "123456789.12345678",
+		ExecutedQuantity:        "0.00000001",
+		CumulativeQuoteQuantity: "0.00000000",
+	}
+	if o.Exact != expected {
+		t.Error("Order decimals are not kept as sent:", expected, o.Exact)
+	}
+
+	if o.Exact.Price.Rat().Cmp(big.NewRat(1, 100000000)) != 0 || o.Price != 0.00000001 {
+		t.Error("Order price should be 0.00000001 but is", o.Exact.Price, o.Price)
+	}
+}
+
+func TestNewExactOrder(t *testing.T) {
+	o := NewExactOrder(Order{Price: 0.00000001, Quantity: 2})
+	if o.Exact.Price != "0.00000001" || o.Exact.Quantity != "2" || o.Exact.StopPrice != "0" {
+		t.Error("Order decimals are not correct:", o.Exact)
+	}
+}
+
+func TestOrderFromBinance_IncorrectPrice(t *testing.T) {
+	if _, err := exactOrderFromBinance(binance.Order{Price: "error"}); err == nil {
+		t.Error("There should be an error on price")
+	}
+}
+
+func TestCreateOrderResponseToOrder(t *testing.T) {
+	r := OrderRequest{StopPrice: 11}
+	o, err := createOrderResponseToExactOrder(binance.CreateOrderResponse{
+		Symbol:           "BTCUSDT",
+		OrderID:          42,
+		Price:            "10",
+		OrigQuantity:     "2",
+		ExecutedQuantity: "0",
+		Status:           binance.OrderStatusTypeNew,
+		TransactTime:     1257894000000,
+	}, r)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.ID != 42 || o.Price != 10 || o.StopPrice != 11 || o.Quantity != 2 || o.Status != OrderStatusNew {
+		t.Error("Order is not converted correctly:", o)
+	}
+
+	if !o.Time.Equal(time.Unix(1257894000, 0)) {
+		t.Error("Order time is not converted correctly:", o.Time)
+	}
+}
+
+func TestCancelOrderResponseToOrder(t *testing.T) {
+	o, err := cancelOrderResponseToExactOrder(binance.CancelOrderResponse{
+		Symbol:            "BTCUSDT",
+		OrderID:           42,
+		OrigClientOrderID: "client",
+		Price:             "10",
+		OrigQuantity:      "2",
+		ExecutedQuantity:  "1",
+		Status:            binance.OrderStatusTypeCanceled,
+	})
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.ID != 42 || o.ClientOrderID != "client" || o.ExecutedQuantity != 1 || o.Status != OrderStatusCanceled {
+		t.Error("Order is not converted correctly:", o)
+	}
+}
+
+func TestCreateOrderDo_TestOrders(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	s := NewWithTestOrders("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	o, err := s.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(OrderSideBuy).
+		Type(OrderTypeLimit).
+		Quantity(1).
+		Price(10).
+		ClientOrderID("client").
+		Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if len(paths) != 1 || paths[0] != "/api/v3/order/test" {
+		t.Error("Order should only be sent to test endpoint, but was sent to", paths)
+	}
+
+	if o.ID != 0 || o.ClientOrderID != "client" || o.Status != OrderStatusNew || o.TimeInForce != TimeInForceGTC {
+		t.Error("Acknowledgement is not correct:", o)
+	}
+}
+
+func TestCreateOrderDo_Reused(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		queries = append(queries, r.Form)
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	s := NewWithTestOrders("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	service := s.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(OrderSideBuy).
+		Type(OrderTypeStopLossLimit).
+		Quantity(1).
+		Price(10).
+		StopPrice(11)
+	if _, err := service.Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	// Reuse the service for a market order, without price nor stop price
+	_, err := service.Type(OrderTypeMarket).Price(0).StopPrice(0).TimeInForce("").Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatal("There should be 2 requests but there is", len(queries))
+	}
+	if queries[0].Get("price") != "10" || queries[0].Get("stopPrice") != "11" {
+		t.Error("First order should have a price and a stop price:", queries[0])
+	}
+	if _, ok := queries[1]["price"]; ok {
+		t.Error("Second order should have no price:", queries[1])
+	}
+	if _, ok := queries[1]["stopPrice"]; ok {
+		t.Error("Second order should have no stop price:", queries[1])
+	}
+}
+
+func TestCreateOrderDo_TestOrdersError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":-1013,"msg":"Filter failure: LOT_SIZE"}`)
+	}))
+	defer server.Close()
+
+	s := NewWithTestOrders("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	_, err := s.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(OrderSideBuy).
+		Type(OrderTypeMarket).
+		Quantity(0.0000001).
+		Do(context.TODO())
+	if err == nil {
+		t.Error("There should be an error from test endpoint")
+	}
+}
+
+func TestCreateOrderDo_TestOrdersInvalid(t *testing.T) {
+	s := NewWithTestOrders("key", "secret")
+
+	_, err := s.NewCreateOrderService().Symbol("BTCUSDT").Do(context.TODO())
+	if err != ErrOrderInvalidSide {
+		t.Error("There should be an error on side, but there is", err)
+	}
+}
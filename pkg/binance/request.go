@@ -0,0 +1,110 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cryptellation/binance.go/pkg/adapters"
+	"github.com/cryptellation/models.go"
+)
+
+// CandleStickRequest is a request for candlesticks. It is a value: it can be
+// copied, compared and executed concurrently, and its methods return modified
+// copies without changing it.
+type CandleStickRequest struct {
+	Symbol string
+	Period int64
+	// StartTime and EndTime are the inclusive bounds of the candlesticks open
+	// times, they are not set if zero
+	StartTime time.Time
+	EndTime   time.Time
+	// Limit is the maximum number of candlesticks, the default limit is used
+	// if it is 0
+	Limit      int
+	Validation ValidationPolicy
+}
+
+// WithSymbol will return a copy of the request with the symbol
+func (r CandleStickRequest) WithSymbol(symbol string) CandleStickRequest {
+	r.Symbol = symbol
+	return r
+}
+
+// WithPeriod will return a copy of the request with the period
+func (r CandleStickRequest) WithPeriod(period int64) CandleStickRequest {
+	r.Period = period
+	return r
+}
+
+// WithStartTime will return a copy of the request with the start time
+func (r CandleStickRequest) WithStartTime(startTime time.Time) CandleStickRequest {
+	r.StartTime = startTime
+	return r
+}
+
+// WithEndTime will return a copy of the request with the end time
+func (r CandleStickRequest) WithEndTime(endTime time.Time) CandleStickRequest {
+	r.EndTime = endTime
+	return r
+}
+
+// WithLimit will return a copy of the request with the limit
+func (r CandleStickRequest) WithLimit(limit int) CandleStickRequest {
+	r.Limit = limit
+	return r
+}
+
+// WithValidation will return a copy of the request with the validation policy
+func (r CandleStickRequest) WithValidation(policy ValidationPolicy) CandleStickRequest {
+	r.Validation = policy
+	return r
+}
+
+// Equal will return true if both requests are the same, with times compared
+// regardless of their location
+func (r CandleStickRequest) Equal(o CandleStickRequest) bool {
+	return r.Symbol == o.Symbol &&
+		r.Period == o.Period &&
+		r.StartTime.Equal(o.StartTime) &&
+		r.EndTime.Equal(o.EndTime) &&
+		r.Limit == o.Limit &&
+		r.Validation == o.Validation
+}
+
+// String will return a readable description of the request, for logs
+func (r CandleStickRequest) String() string {
+	parts := []string{"symbol=" + r.Symbol}
+
+	if interval, err := adapters.PeriodToInterval(r.Period); err == nil {
+		parts = append(parts, "interval="+interval)
+	} else {
+		parts = append(parts, fmt.Sprintf("period=%d", r.Period))
+	}
+	if !r.StartTime.IsZero() {
+		parts = append(parts, "start="+r.StartTime.UTC().Format(time.RFC3339Nano))
+	}
+	if !r.EndTime.IsZero() {
+		parts = append(parts, "end="+r.EndTime.UTC().Format(time.RFC3339Nano))
+	}
+	if r.Limit != 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", r.Limit))
+	}
+	if r.Validation != ValidationPolicyNone {
+		parts = append(parts, "validation="+string(r.Validation))
+	}
+
+	return "candlesticks{" + strings.Join(parts, " ") + "}"
+}
+
+// Do will execute the request on the service
+func (r CandleStickRequest) Do(ctx context.Context, s ServiceInterface) ([]models.CandleStick, error) {
+	return s.NewCandleStickService().WithRequest(r).Do(ctx)
+}
+
+// DoExact will execute the request on the service and keep the candlesticks
+// exact decimal values and close times
+func (r CandleStickRequest) DoExact(ctx context.Context, s ServiceInterface) ([]ExactCandleStick, error) {
+	return s.NewCandleStickService().WithRequest(r).DoExact(ctx)
+}
@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func TestCandleStickRequest_Immutable(t *testing.T) {
+	base := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}
+
+	other := base.WithSymbol("ETHUSDT").WithLimit(10).WithValidation(ValidationPolicyDrop)
+	if base.Symbol != "BTCUSDT" || base.Limit != 0 || base.Validation != ValidationPolicyNone {
+		t.Error("Base request should not be modified:", base)
+	}
+	if other.Symbol != "ETHUSDT" || other.Period != models.M1 || other.Limit != 10 || other.Validation != ValidationPolicyDrop {
+		t.Error("Request is not correct:", other)
+	}
+}
+
+func TestCandleStickRequest_Equal(t *testing.T) {
+	start := time.Unix(60, 0)
+	a := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}.WithStartTime(start)
+	b := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}.WithStartTime(start.UTC())
+
+	if !a.Equal(b) {
+		t.Error("Requests should be equal")
+	}
+	if a.Equal(b.WithLimit(10)) {
+		t.Error("Requests should not be equal")
+	}
+}
+
+func TestCandleStickRequest_String(t *testing.T) {
+	r := CandleStickRequest{
+		Symbol:    "BTCUSDT",
+		Period:    models.M15,
+		StartTime: time.Unix(60, 0),
+		Limit:     10,
+	}
+
+	expected := "candlesticks{symbol=BTCUSDT interval=15m start=1970-01-01T00:01:00Z limit=10}"
+	if r.String() != expected {
+		t.Error("String should be", expected, "but is", r.String())
+	}
+}
+
+func TestCandleStickRequest_DoConcurrently(t *testing.T) {
+	var mutex sync.Mutex
+	queries := make(map[string]url.Values)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		queries[r.URL.Query().Get("symbol")] = r.URL.Query()
+		mutex.Unlock()
+		fmt.Fprint(w, "[]")
+	}))
+	defer server.Close()
+
+	s := New("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+
+	base := CandleStickRequest{Period: models.H1, EndTime: time.Unix(3600, 0)}
+	symbols := []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "ADAUSDT"}
+
+	var wg sync.WaitGroup
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(r CandleStickRequest) {
+			defer wg.Done()
+			if _, err := r.Do(context.TODO(), s); err != nil {
+				t.Error("There should be no error but there is", err)
+			}
+		}(base.WithSymbol(symbol))
+	}
+	wg.Wait()
+
+	for _, symbol := range symbols {
+		q, ok := queries[symbol]
+		if !ok {
+			t.Error("There should be a request for", symbol)
+			continue
+		}
+		if q.Get("interval") != "1h" || q.Get("endTime") != "3600000" || q.Get("limit") != "" || q.Get("startTime") != "" {
+			t.Error("Request for", symbol, "is not correct:", q)
+		}
+	}
+}
+
+func TestCandleStickService_Request(t *testing.T) {
+	s := New("key", "secret").NewCandleStickService().
+		Symbol("BTCUSDT").
+		Period(models.M1).
+		Limit(10)
+
+	expected := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1, Limit: 10}
+	if !s.Request().Equal(expected) {
+		t.Error("Request should be", expected, "but is", s.Request())
+	}
+
+	r := s.WithRequest(expected.WithSymbol("ETHUSDT")).Request()
+	if r.Symbol != "ETHUSDT" {
+		t.Error("Request should have been replaced but is", r)
+	}
+}
@@ -1,24 +1,177 @@
 package binance
 
 import (
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/adshao/go-binance/v2"
 )
 
 // Service represents the real Binance service
 type Service struct {
-	client *binance.Client
+	client     *binance.Client
+	clock      clock
+	wsBaseURL  string
+	recvWindow time.Duration
+	testOrders bool
+	metrics    MetricsInterface
+	tracer     TracerInterface
+}
+
+// New will create a new real binance service, with production endpoints and
+// default HTTP client if no option is specified
+func New(apiKey, secretKey string, options ...Option) ServiceInterface {
+	return newService(apiKey, secretKey, newConfig(options))
 }
 
-// New will create a new real binance service
-func New(apiKey, secretKey string) ServiceInterface {
+// NewWithTestOrders will create a new real binance service where every order
+// creation is only validated by Binance test endpoint, without being sent to
+// the matching engine. Other requests are left untouched.
+func NewWithTestOrders(apiKey, secretKey string, options ...Option) ServiceInterface {
+	return newService(apiKey, secretKey, newConfig(append(options, withTestOrders())))
+}
+
+func newService(apiKey, secretKey string, c config) *Service {
+	client := binance.NewClient(apiKey, secretKey)
+	if c.baseURL != "" {
+		client.BaseURL = c.baseURL
+	}
+	if httpClient := c.newHTTPClient(); httpClient != nil {
+		client.HTTPClient = httpClient
+	}
+
+	var metrics MetricsInterface = nopMetrics{}
+	if c.metrics != nil {
+		metrics = c.metrics
+	}
+
 	return &Service{
-		client: binance.NewClient(apiKey, secretKey),
+		client:     client,
+		wsBaseURL:  c.wsBaseURL,
+		recvWindow: c.recvWindow,
+		testOrders: c.testOrders,
+		metrics:    metrics,
+		tracer:     c.tracer,
 	}
 }
 
+// offsetClient will return a copy of the client with the current clock
+// offset, as go-binance reads it without synchronization on each request. It
+// is called on each request, so that services use the last measured offset.
+func (s *Service) offsetClient() *binance.Client {
+	c := *s.client
+	c.TimeOffset = -s.ClockOffset().Milliseconds()
+	return &c
+}
+
+// requestOptions will return the options that should be set on signed requests
+func (s *Service) requestOptions() []binance.RequestOption {
+	if s.recvWindow == 0 {
+		return nil
+	}
+
+	return []binance.RequestOption{
+		binance.WithRecvWindow(s.recvWindow.Milliseconds()),
+	}
+}
+
+// wrappingTransport is a RoundTripper wrapping another one
+type wrappingTransport interface {
+	unwrap() http.RoundTripper
+}
+
+// proxy will return the proxy function of the HTTP client transport, if any
+func (s *Service) proxy() func(*http.Request) (*url.URL, error) {
+	transport := s.client.HTTPClient.Transport
+	for {
+		w, ok := transport.(wrappingTransport)
+		if !ok {
+			break
+		}
+		transport = w.unwrap()
+	}
+
+	if t, ok := transport.(*http.Transport); ok {
+		return t.Proxy
+	}
+	return http.ProxyFromEnvironment
+}
+
 // NewCandleStickService will create a new real candlestick service
 func (s *Service) NewCandleStickService() CandleStickServiceInterface {
 	return &CandleStickService{
-		service: s.client.NewKlinesService(),
+		client:  s.offsetClient(),
+		metrics: s.metrics,
+		tracer:  s.tracer,
+	}
+}
+
+// NewAccountService will create a new real account service
+func (s *Service) NewAccountService() AccountServiceInterface {
+	return &AccountService{
+		client:  s.offsetClient,
+		options: s.requestOptions(),
+		tracer:  s.tracer,
+	}
+}
+
+// NewCreateOrderService will create a new real order creation service
+func (s *Service) NewCreateOrderService() CreateOrderServiceInterface {
+	return &CreateOrderService{
+		client:  s.offsetClient,
+		now:     s.Now,
+		options: s.requestOptions(),
+		test:    s.testOrders,
+		tracer:  s.tracer,
+	}
+}
+
+// NewGetOrderService will create a new real order query service
+func (s *Service) NewGetOrderService() GetOrderServiceInterface {
+	return &GetOrderService{
+		client:  s.offsetClient,
+		options: s.requestOptions(),
+		tracer:  s.tracer,
+	}
+}
+
+// NewCancelOrderService will create a new real order cancellation service
+func (s *Service) NewCancelOrderService() CancelOrderServiceInterface {
+	return &CancelOrderService{
+		client:  s.offsetClient,
+		options: s.requestOptions(),
+		tracer:  s.tracer,
+	}
+}
+
+// NewCancelOpenOrdersService will create a new real open orders cancellation service
+func (s *Service) NewCancelOpenOrdersService() CancelOpenOrdersServiceInterface {
+	return &CancelOpenOrdersService{
+		client:  s.offsetClient,
+		options: s.requestOptions(),
+		tracer:  s.tracer,
+	}
+}
+
+// NewListOpenOrdersService will create a new real open orders listing service
+func (s *Service) NewListOpenOrdersService() ListOpenOrdersServiceInterface {
+	return &ListOpenOrdersService{
+		client:  s.offsetClient,
+		options: s.requestOptions(),
+		tracer:  s.tracer,
+	}
+}
+
+// NewUserDataStreamService will create a new real user data stream service
+func (s *Service) NewUserDataStreamService() UserDataStreamServiceInterface {
+	return &UserDataStreamService{
+		client:         s.offsetClient(),
+		wsBaseURL:      s.wsBaseURL,
+		proxy:          s.proxy(),
+		keepAlive:      DefaultUserDataStreamKeepAlive,
+		reconnectDelay: defaultUserDataReconnectDelay,
+		metrics:        s.metrics,
+		tracer:         s.tracer,
 	}
 }
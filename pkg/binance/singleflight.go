@@ -0,0 +1,229 @@
+package binance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+// SingleFlightService is a service that collapses identical concurrent
+// candlestick requests into one request to the wrapped service, and returns
+// its result to every caller. Other services are the wrapped ones.
+type SingleFlightService struct {
+	ServiceInterface
+
+	mutex   sync.Mutex
+	flights map[candleStickRequestKey]*flight
+}
+
+// NewSingleFlight will wrap the service so identical concurrent candlestick
+// requests are only executed once
+func NewSingleFlight(s ServiceInterface) ServiceInterface {
+	return &SingleFlightService{
+		ServiceInterface: s,
+		flights:          make(map[candleStickRequestKey]*flight),
+	}
+}
+
+// NewCandleStickService will create a new candlestick service which requests
+// are shared with identical concurrent requests
+func (s *SingleFlightService) NewCandleStickService() CandleStickServiceInterface {
+	return &singleFlightCandleStickService{parent: s}
+}
+
+// candleStickRequestKey identifies identical candlestick requests
+type candleStickRequestKey struct {
+	symbol     string
+	period     int64
+	startTime  time.Time
+	endTime    time.Time
+	limit      int
+	validation ValidationPolicy
+}
+
+func newCandleStickRequestKey(r CandleStickRequest) candleStickRequestKey {
+	return candleStickRequestKey{
+		symbol:     r.Symbol,
+		period:     r.Period,
+		startTime:  r.StartTime.Round(0).UTC(),
+		endTime:    r.EndTime.Round(0).UTC(),
+		limit:      r.Limit,
+		validation: r.Validation,
+	}
+}
+
+// flight is a candlestick request in progress on the wrapped service
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	cs  []ExactCandleStick
+	err error
+}
+
+// do will execute the request, or wait for the identical one in progress. The
+// wrapped request is only canceled when every caller has canceled.
+func (s *SingleFlightService) do(ctx context.Context, r CandleStickRequest) ([]ExactCandleStick, error) {
+	key := newCandleStickRequestKey(r)
+
+	s.mutex.Lock()
+	f, exists := s.flights[key]
+	if !exists {
+		flightCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		s.flights[key] = f
+		go s.run(flightCtx, key, f, r)
+	}
+	f.waiters++
+	s.mutex.Unlock()
+
+	select {
+	case <-f.done:
+		return copyExactCandleSticks(f.cs), f.err
+	case <-ctx.Done():
+		s.leave(key, f)
+		return nil, ctx.Err()
+	}
+}
+
+// run will execute the request on the wrapped service and release the callers
+func (s *SingleFlightService) run(ctx context.Context, key candleStickRequestKey, f *flight, r CandleStickRequest) {
+	f.cs, f.err = s.ServiceInterface.NewCandleStickService().WithRequest(r).DoExact(ctx)
+	f.cancel()
+
+	s.mutex.Lock()
+	if s.flights[key] == f {
+		delete(s.flights, key)
+	}
+	s.mutex.Unlock()
+
+	close(f.done)
+}
+
+// leave will remove a canceled caller from the flight, and cancel the flight
+// if it was the last one
+func (s *SingleFlightService) leave(key candleStickRequestKey, f *flight) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	f.cancel()
+	if s.flights[key] == f {
+		delete(s.flights, key)
+	}
+}
+
+// detachedContext is a context keeping the values of its parent, such as its
+// tracing span, without its deadline and cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// copyExactCandleSticks will copy the candlesticks, so each caller can modify
+// its own candlesticks
+func copyExactCandleSticks(cs []ExactCandleStick) []ExactCandleStick {
+	if cs == nil {
+		return nil
+	}
+
+	cp := make([]ExactCandleStick, len(cs))
+	for i, c := range cs {
+		cp[i] = c
+		if c.Violations != nil {
+			cp[i].Violations = append([]Violation(nil), c.Violations...)
+		}
+	}
+	return cp
+}
+
+// singleFlightCandleStickService is the candlestick service of SingleFlightService
+type singleFlightCandleStickService struct {
+	parent  *SingleFlightService
+	request CandleStickRequest
+}
+
+// Do will execute a request for candlesticks, or wait for the identical one
+// in progress
+func (s *singleFlightCandleStickService) Do(ctx context.Context) ([]models.CandleStick, error) {
+	ecs, err := s.DoExact(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := make([]models.CandleStick, len(ecs))
+	for i, c := range ecs {
+		cs[i] = c.CandleStick
+	}
+	return cs, nil
+}
+
+// DoExact will execute a request for candlesticks and keep their exact
+// decimal values and close times, or wait for the identical one in progress
+func (s *singleFlightCandleStickService) DoExact(ctx context.Context) ([]ExactCandleStick, error) {
+	return s.parent.do(ctx, s.request)
+}
+
+// Request will return the request that the service will execute
+func (s *singleFlightCandleStickService) Request() CandleStickRequest {
+	return s.request
+}
+
+// WithRequest will replace the request that the service will execute
+func (s *singleFlightCandleStickService) WithRequest(r CandleStickRequest) CandleStickServiceInterface {
+	s.request = r
+	return s
+}
+
+// Symbol will specify a symbol for next candlesticks request
+func (s *singleFlightCandleStickService) Symbol(symbol string) CandleStickServiceInterface {
+	s.request = s.request.WithSymbol(symbol)
+	return s
+}
+
+// Period will specify a period for next candlesticks request
+func (s *singleFlightCandleStickService) Period(period int64) CandleStickServiceInterface {
+	s.request = s.request.WithPeriod(period)
+	return s
+}
+
+// StartTime will specify the time where the list starts (earliest time) for
+// next candlesticks request
+func (s *singleFlightCandleStickService) StartTime(startTime time.Time) CandleStickServiceInterface {
+	s.request = s.request.WithStartTime(startTime)
+	return s
+}
+
+// EndTime will specify the time where the list ends (latest time) for
+// next candlesticks request
+func (s *singleFlightCandleStickService) EndTime(endTime time.Time) CandleStickServiceInterface {
+	s.request = s.request.WithEndTime(endTime)
+	return s
+}
+
+// Limit will specify the number of candlesticks the list should have at its maximum
+func (s *singleFlightCandleStickService) Limit(limit int) CandleStickServiceInterface {
+	s.request = s.request.WithLimit(limit)
+	return s
+}
+
+// Validation will specify the policy applied to invalid candlesticks for next
+// candlesticks request
+func (s *singleFlightCandleStickService) Validation(policy ValidationPolicy) CandleStickServiceInterface {
+	s.request = s.request.WithValidation(policy)
+	return s
+}
@@ -0,0 +1,147 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+// newSingleFlightTestServer will return a server answering one kline per
+// request once released, and its requests count
+func newSingleFlightTestServer(t *testing.T, release chan struct{}) (ServiceInterface, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprint(w, `[[60000,"1","2","0.5","1.5","10",119999,"15",1,"5","7","0"]]`)
+	}))
+	t.Cleanup(server.Close)
+
+	s := New("key", "secret").(*Service)
+	s.client.BaseURL = server.URL
+	return NewSingleFlight(s), &count
+}
+
+// waiters will return the number of callers waiting for a request
+func waiters(s *SingleFlightService) (count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, f := range s.flights {
+		count += f.waiters
+	}
+	return count
+}
+
+func TestSingleFlight_Collapse(t *testing.T) {
+	release := make(chan struct{})
+	s, count := newSingleFlightTestServer(t, release)
+
+	r := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}
+	results := make([][]models.CandleStick, 5)
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cs, err := r.Do(context.TODO(), s)
+			if err != nil {
+				t.Error("There should be no error but there is", err)
+			}
+			results[i] = cs
+		}(i)
+	}
+
+	// Wait for every caller to join the request before releasing it
+	for waiters(s.(*SingleFlightService)) != len(results) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if c := atomic.LoadInt32(count); c != 1 {
+		t.Error("There should be 1 request but there is", c)
+	}
+
+	for i, cs := range results {
+		if len(cs) != 1 || cs[0].Open != 1 {
+			t.Fatal("Result", i, "is not correct:", cs)
+		}
+	}
+
+	// Each caller has its own copy
+	results[0][0].Open = 42
+	if results[1][0].Open != 1 {
+		t.Error("Results should not be shared between callers")
+	}
+}
+
+func TestSingleFlight_DifferentRequests(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	s, count := newSingleFlightTestServer(t, release)
+
+	r := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}
+	for _, req := range []CandleStickRequest{r, r.WithSymbol("ETHUSDT"), r.WithLimit(10)} {
+		if _, err := req.Do(context.TODO(), s); err != nil {
+			t.Error("There should be no error but there is", err)
+		}
+	}
+
+	if c := atomic.LoadInt32(count); c != 3 {
+		t.Error("There should be 3 requests but there is", c)
+	}
+}
+
+func TestSingleFlight_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	s, _ := newSingleFlightTestServer(t, release)
+
+	r := CandleStickRequest{Symbol: "BTCUSDT", Period: models.M1}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error)
+	go func() {
+		_, err := r.Do(ctx, s)
+		errs <- err
+	}()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := r.Do(ctx, s)
+		errs <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Error("First caller should be canceled but there is", err)
+	}
+
+	// The other caller is still waiting until its own deadline
+	if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Second caller should exceed its deadline but there is", err)
+	}
+
+	// Every caller left, so the request has been abandoned
+	sf := s.(*SingleFlightService)
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	if len(sf.flights) != 0 {
+		t.Error("There should be no request in progress but there is", len(sf.flights))
+	}
+}
@@ -0,0 +1,334 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// Span attributes keys
+const (
+	// AttributeSymbol is the key of the requested symbol
+	AttributeSymbol = "binance.symbol"
+	// AttributePeriod is the key of the requested candlesticks period, in seconds
+	AttributePeriod = "binance.period"
+	// AttributeInterval is the key of the requested candlesticks interval (i.e. "1m")
+	AttributeInterval = "binance.interval"
+	// AttributeLimit is the key of the requested limit
+	AttributeLimit = "binance.limit"
+	// AttributeResponseSize is the key of the number of returned items
+	AttributeResponseSize = "binance.response.size"
+	// AttributeRetryAttempt is the key of the attempt number of a retried
+	// operation, starting at 0 for the first attempt
+	AttributeRetryAttempt = "binance.retry.attempt"
+	// AttributeErrorClass is the key of the error class, as returned by ClassifyError
+	AttributeErrorClass = "binance.error.class"
+	// AttributeErrorCode is the key of the Binance API error code
+	AttributeErrorCode = "binance.error.code"
+	// AttributeHTTPMethod is the key of the HTTP request method
+	AttributeHTTPMethod = "http.method"
+	// AttributeHTTPPath is the key of the HTTP request path
+	AttributeHTTPPath = "http.path"
+	// AttributeHTTPStatusCode is the key of the HTTP response status code
+	AttributeHTTPStatusCode = "http.status_code"
+)
+
+// Error classes returned by ClassifyError
+const (
+	// ErrorClassCanceled is the class of errors from canceled contexts
+	ErrorClassCanceled = "canceled"
+	// ErrorClassTimeout is the class of errors from expired deadlines
+	ErrorClassTimeout = "timeout"
+	// ErrorClassRateLimited is the class of errors from Binance rate limits
+	ErrorClassRateLimited = "rate_limited"
+	// ErrorClassAPI is the class of other errors returned by Binance API
+	ErrorClassAPI = "api"
+	// ErrorClassNetwork is the class of connection errors
+	ErrorClassNetwork = "network"
+	// ErrorClassDecode is the class of errors on responses decoding
+	ErrorClassDecode = "decode"
+	// ErrorClassInvalid is the class of errors on invalid requests or responses
+	// detected before or after calling Binance
+	ErrorClassInvalid = "invalid"
+	// ErrorClassUnknown is the class of other errors
+	ErrorClassUnknown = "unknown"
+)
+
+// Span names
+const (
+	// SpanCandleSticks is the name of the spans around candlesticks requests
+	SpanCandleSticks = "binance.candlesticks"
+	// SpanAccount is the name of the spans around account requests
+	SpanAccount = "binance.account"
+	// SpanCreateOrder is the name of the spans around order creations
+	SpanCreateOrder = "binance.order.create"
+	// SpanGetOrder is the name of the spans around order queries
+	SpanGetOrder = "binance.order.get"
+	// SpanCancelOrder is the name of the spans around order cancellations
+	SpanCancelOrder = "binance.order.cancel"
+	// SpanCancelOpenOrders is the name of the spans around open orders cancellations
+	SpanCancelOpenOrders = "binance.order.cancel_open"
+	// SpanListOpenOrders is the name of the spans around open orders listings
+	SpanListOpenOrders = "binance.order.list_open"
+	// SpanUserDataStreamConnect is the name of the spans around user data
+	// stream connections, with the retry attempt
+	SpanUserDataStreamConnect = "binance.user_data.connect"
+	// SpanHTTP is the name of the spans around HTTP requests, from the request
+	// to the end of the response body
+	SpanHTTP = "binance.http"
+	// SpanDecode is the name of the spans around JSON decoding of the responses
+	SpanDecode = "binance.decode"
+	// SpanConvert is the name of the spans around the conversion of Binance
+	// klines to candlesticks
+	SpanConvert = "binance.convert"
+	// SpanValidate is the name of the spans around candlesticks validation
+	SpanValidate = "binance.validate"
+)
+
+// Attribute is a key-value attribute of a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// SpanInterface is the interface for spans created by a tracer
+type SpanInterface interface {
+	// SetAttributes will set the attributes on the span
+	SetAttributes(attributes ...Attribute)
+	// RecordError will record the error on the span and set its status as failed
+	RecordError(err error)
+	// End will end the span
+	End()
+}
+
+// TracerInterface is the interface for tracers of the real service, it can
+// be implemented on top of any tracing library (i.e. OpenTelemetry)
+type TracerInterface interface {
+	// Start will start a span as a child of the span of the context, if any,
+	// and return a context holding the new span
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, SpanInterface)
+}
+
+// ClassifyError will return the class of an error returned by the services
+func ClassifyError(err error) string {
+	var apiErr *common.APIError
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.As(err, &apiErr):
+		// Too many requests or too many orders
+		if apiErr.Code == -1003 || apiErr.Code == -1015 {
+			return ErrorClassRateLimited
+		}
+		return ErrorClassAPI
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr), errors.Is(err, ErrInvalidDecimal):
+		return ErrorClassDecode
+	case isInvalidError(err):
+		return ErrorClassInvalid
+	default:
+		return ErrorClassUnknown
+	}
+}
+
+func isInvalidError(err error) bool {
+	for _, e := range []error{
+		ErrInvalidCandleSticks,
+		ErrOrderNoSymbol,
+		ErrOrderInvalidSide,
+		ErrOrderInvalidType,
+		ErrOrderInvalidQuantity,
+		ErrOrderInvalidPrice,
+		ErrOrderInvalidStopPrice,
+		ErrOrderInvalidTimeInForce,
+		ErrOrderNoIdentifier,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// span is a nil-safe span, doing nothing without tracer
+type span struct {
+	span SpanInterface
+}
+
+// startSpan will start a span with the tracer, if any
+func startSpan(ctx context.Context, tracer TracerInterface, name string, attributes ...Attribute) (context.Context, span) {
+	if tracer == nil {
+		return ctx, span{}
+	}
+
+	ctx, s := tracer.Start(ctx, name, attributes...)
+	return ctx, span{span: s}
+}
+
+func (s span) setAttributes(attributes ...Attribute) {
+	if s.span != nil {
+		s.span.SetAttributes(attributes...)
+	}
+}
+
+// end will record the error with its class, if any, and end the span
+func (s span) end(err error) {
+	if s.span == nil {
+		return
+	}
+
+	if err != nil {
+		attributes := []Attribute{{Key: AttributeErrorClass, Value: ClassifyError(err)}}
+		var apiErr *common.APIError
+		if errors.As(err, &apiErr) {
+			attributes = append(attributes, Attribute{Key: AttributeErrorCode, Value: apiErr.Code})
+		}
+		s.span.SetAttributes(attributes...)
+		s.span.RecordError(err)
+	}
+	s.span.End()
+}
+
+// decodePhase is set in the context of a call to go-binance, so the decoding
+// span can be started by the transport at the end of the response body and
+// ended by the caller when go-binance returns
+type decodePhase struct {
+	mutex  sync.Mutex
+	ctx    context.Context
+	tracer TracerInterface
+	span   span
+}
+
+type decodePhaseKey struct{}
+
+// withDecodePhase will return a context where the transport can start the
+// decoding span
+func withDecodePhase(ctx context.Context, tracer TracerInterface) (context.Context, *decodePhase) {
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	p := &decodePhase{ctx: ctx, tracer: tracer}
+	return context.WithValue(ctx, decodePhaseKey{}, p), p
+}
+
+func (p *decodePhase) start() {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.span.end(nil)
+	_, p.span = startSpan(p.ctx, p.tracer, SpanDecode)
+}
+
+// end will end the decoding span, if started
+func (p *decodePhase) end(err error) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.span.end(err)
+	p.span = span{}
+}
+
+// call will execute a go-binance call in a context where the transport can
+// start the decoding span, which is ended when the call returns
+func call(ctx context.Context, tracer TracerInterface, fn func(ctx context.Context) error) error {
+	ctx, phase := withDecodePhase(ctx, tracer)
+	err := fn(ctx)
+	phase.end(err)
+	return err
+}
+
+// tracingTransport is a RoundTripper creating spans around HTTP requests
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer TracerInterface
+}
+
+// RoundTrip will forward the request in a span ended with the response body
+func (t *tracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	ctx, s := startSpan(r.Context(), t.tracer, SpanHTTP,
+		Attribute{Key: AttributeHTTPMethod, Value: r.Method},
+		Attribute{Key: AttributeHTTPPath, Value: r.URL.Path})
+
+	res, err := next.RoundTrip(r.WithContext(ctx))
+	if err != nil {
+		s.end(err)
+		return res, err
+	}
+
+	s.setAttributes(Attribute{Key: AttributeHTTPStatusCode, Value: res.StatusCode})
+	phase, _ := r.Context().Value(decodePhaseKey{}).(*decodePhase)
+	res.Body = &tracingBody{ReadCloser: res.Body, span: s, phase: phase}
+	return res, nil
+}
+
+func (t *tracingTransport) unwrap() http.RoundTripper {
+	return t.next
+}
+
+// tracingBody is a response body ending the HTTP span when it is completely
+// read or closed, and starting the decoding span
+type tracingBody struct {
+	io.ReadCloser
+	once  sync.Once
+	span  span
+	phase *decodePhase
+}
+
+func (b *tracingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.finish(nil)
+	} else if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+func (b *tracingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+	return err
+}
+
+func (b *tracingBody) finish(err error) {
+	b.once.Do(func() {
+		b.span.end(err)
+		if err == nil {
+			b.phase.start()
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/models.go"
+)
+
+// recordedSpan is a span recorded by recordingTracer
+type recordedSpan struct {
+	tracer     *recordingTracer
+	name       string
+	parent     *recordedSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttributes(attributes ...Attribute) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.err = err
+}
+
+func (s *recordedSpan) End() {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.ended = true
+}
+
+type recordedSpanKey struct{}
+
+// recordingTracer is a tracer recording the spans it creates
+type recordingTracer struct {
+	mutex sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, SpanInterface) {
+	s := &recordedSpan{tracer: t, name: name, attributes: make(map[string]interface{})}
+	s.parent, _ = ctx.Value(recordedSpanKey{}).(*recordedSpan)
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, recordedSpanKey{}, s), s
+}
+
+// span will return the first span with the name, or nil
+func (t *recordingTracer) span(name string) *recordedSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, s := range t.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func newTracingTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("symbol") {
+		case "LIMITED":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"code":-1003,"msg":"Too many requests."}`)
+		case "INVALID":
+			fmt.Fprint(w, `[[60000,"1","2","0.5","abc","10",119999,"15",1,"5","7","0"]]`)
+		default:
+			fmt.Fprint(w, `[[60000,"1","2","0.5","1.5","10",119999,"15",1,"5","7","0"]]`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTracing_CandleSticks(t *testing.T) {
+	tracer := &recordingTracer{}
+	s := New("key", "secret", WithBaseURL(newTracingTestServer(t).URL), WithTracer(tracer))
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	cs, err := s.NewCandleStickService().
+		Symbol("BTCUSDT").
+		Period(models.M1).
+		Limit(10).
+		Validation(ValidationPolicyReject).
+		Do(ctx)
+	root.End()
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	call := tracer.span(SpanCandleSticks)
+	if call == nil {
+		t.Fatal("There should be a candlesticks span")
+	} else if call.parent != root {
+		t.Error("The candlesticks span should be a child of the caller span")
+	} else if !call.ended || call.err != nil {
+		t.Error("The candlesticks span should be ended without error:", call.ended, call.err)
+	}
+
+	expected := map[string]interface{}{
+		AttributeSymbol:       "BTCUSDT",
+		AttributePeriod:       models.M1,
+		AttributeInterval:     "1m",
+		AttributeLimit:        10,
+		AttributeResponseSize: len(cs),
+	}
+	for k, v := range expected {
+		if call.attributes[k] != v {
+			t.Error("There should be", v, "for", k, "but there is", call.attributes[k])
+		}
+	}
+
+	for _, name := range []string{SpanHTTP, SpanDecode, SpanConvert, SpanValidate} {
+		child := tracer.span(name)
+		if child == nil {
+			t.Error("There should be a span", name)
+		} else if child.parent != call {
+			t.Error("The span", name, "should be a child of the candlesticks span")
+		} else if !child.ended {
+			t.Error("The span", name, "should be ended")
+		}
+	}
+
+	if http := tracer.span(SpanHTTP); http != nil {
+		if http.attributes[AttributeHTTPStatusCode] != 200 || http.attributes[AttributeHTTPPath] != "/api/v3/klines" {
+			t.Error("The HTTP span has wrong attributes:", http.attributes)
+		}
+	}
+}
+
+func TestTracing_CandleSticksErrors(t *testing.T) {
+	cases := []struct {
+		Symbol string
+		Class  string
+	}{
+		{Symbol: "LIMITED", Class: ErrorClassRateLimited},
+		{Symbol: "INVALID", Class: ErrorClassDecode},
+	}
+
+	server := newTracingTestServer(t)
+	for _, c := range cases {
+		tracer := &recordingTracer{}
+		s := New("key", "secret", WithBaseURL(server.URL), WithTracer(tracer))
+
+		_, err := s.NewCandleStickService().Symbol(c.Symbol).Period(models.M1).Do(context.Background())
+		if err == nil {
+			t.Error("There should be an error for", c.Symbol)
+			continue
+		}
+
+		call := tracer.span(SpanCandleSticks)
+		if call == nil {
+			t.Error("There should be a candlesticks span for", c.Symbol)
+			continue
+		} else if call.err != err {
+			t.Error("The span should have recorded the error", err, "but there is", call.err)
+		} else if call.attributes[AttributeErrorClass] != c.Class {
+			t.Error("There should be the class", c.Class, "but there is", call.attributes[AttributeErrorClass])
+		}
+	}
+}
+
+func TestTracing_Orders(t *testing.T) {
+	tracer := &recordingTracer{}
+	s := New("key", "secret", WithTracer(tracer))
+
+	_, err := s.NewCreateOrderService().Side(OrderSideBuy).Type(OrderTypeMarket).Quantity(1).Do(context.Background())
+	if !errors.Is(err, ErrOrderNoSymbol) {
+		t.Fatal("There should be a no symbol error but there is", err)
+	}
+
+	call := tracer.span(SpanCreateOrder)
+	if call == nil {
+		t.Fatal("There should be an order creation span")
+	} else if !call.ended || call.attributes[AttributeErrorClass] != ErrorClassInvalid {
+		t.Error("The span should be ended with an invalid error:", call.ended, call.attributes)
+	}
+
+	if tracer.span(SpanHTTP) != nil {
+		t.Error("There should be no HTTP request for an invalid order")
+	}
+}
+
+func TestTracing_NoTracer(t *testing.T) {
+	s := New("key", "secret", WithBaseURL(newTracingTestServer(t).URL)).(*Service)
+	if s.client.HTTPClient.Transport != nil {
+		t.Error("There should be no transport without tracer")
+	}
+
+	if _, err := s.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(context.Background()); err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+}
+
+func TestTracing_Proxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy:8080")
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	s := New("key", "secret",
+		WithTransport(transport),
+		WithMetrics(newRecordingMetrics()),
+		WithTracer(&recordingTracer{})).(*Service)
+
+	u, err := s.proxy()(&http.Request{URL: &url.URL{}})
+	if err != nil || u == nil || u.String() != proxyURL.String() {
+		t.Error("The proxy should be kept through the wrapping transports but there is", u, err)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		Err   error
+		Class string
+	}{
+		{Err: nil, Class: ""},
+		{Err: context.Canceled, Class: ErrorClassCanceled},
+		{Err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), Class: ErrorClassTimeout},
+		{Err: &common.APIError{Code: -1003}, Class: ErrorClassRateLimited},
+		{Err: &common.APIError{Code: -1015}, Class: ErrorClassRateLimited},
+		{Err: &common.APIError{Code: -2011}, Class: ErrorClassAPI},
+		{Err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}, Class: ErrorClassNetwork},
+		{Err: &json.SyntaxError{}, Class: ErrorClassDecode},
+		{Err: &strconv.NumError{Func: "ParseFloat", Err: strconv.ErrSyntax}, Class: ErrorClassDecode},
+		{Err: ErrInvalidDecimal, Class: ErrorClassDecode},
+		{Err: &ValidationError{}, Class: ErrorClassInvalid},
+		{Err: ErrOrderNoIdentifier, Class: ErrorClassInvalid},
+		{Err: errors.New("other"), Class: ErrorClassUnknown},
+	}
+
+	for i, c := range cases {
+		if class := ClassifyError(c.Err); class != c.Class {
+			t.Error("There should be the class", c.Class, "for case", i, "but there is", class)
+		}
+	}
+}
+
+func TestTracing_SingleFlight(t *testing.T) {
+	tracer := &recordingTracer{}
+	s := NewSingleFlight(New("key", "secret", WithBaseURL(newTracingTestServer(t).URL), WithTracer(tracer)))
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, err := s.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(ctx)
+	root.End()
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if call := tracer.span(SpanCandleSticks); call == nil || call.parent != root {
+		t.Error("The shared request span should be a child of the first caller span")
+	}
+}
@@ -0,0 +1,563 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultUserDataStreamKeepAlive is the default interval between two
+	// keepalives of the user data stream listen key. Binance closes the
+	// listen key after 60 minutes without keepalive.
+	DefaultUserDataStreamKeepAlive = 30 * time.Minute
+
+	// defaultWebSocketBaseURL is the base URL of Binance websocket streams
+	defaultWebSocketBaseURL = "wss://stream.binance.com:9443/ws"
+
+	// userDataEventsBufferSize is the size of the user data events channel
+	userDataEventsBufferSize = 64
+
+	// defaultUserDataReconnectDelay is the delay between two reconnection attempts
+	defaultUserDataReconnectDelay = time.Second
+
+	// userDataHandshakeTimeout is the timeout of the websocket connection and handshake
+	userDataHandshakeTimeout = 45 * time.Second
+)
+
+var (
+	// ErrUserDataListenKeyExpired is reported when the user data stream listen
+	// key has expired and the stream has to reconnect
+	ErrUserDataListenKeyExpired = errors.New("user data stream error: listen key expired")
+	// ErrUserDataInvalidKeepAlive is returned when the user data stream is
+	// started with a keepalive interval that is not positive
+	ErrUserDataInvalidKeepAlive = errors.New("user data stream error: invalid keepalive interval")
+)
+
+// UserDataEventType is the type of an event coming from the user data stream
+type UserDataEventType string
+
+const (
+	// UserDataEventTypeExecutionReport is the type of events on orders updates
+	UserDataEventTypeExecutionReport UserDataEventType = "executionReport"
+	// UserDataEventTypeAccountPosition is the type of events on account
+	// balances changes
+	UserDataEventTypeAccountPosition UserDataEventType = "outboundAccountPosition"
+	// UserDataEventTypeBalanceUpdate is the type of events on deposits,
+	// withdrawals and transfers
+	UserDataEventTypeBalanceUpdate UserDataEventType = "balanceUpdate"
+	// userDataEventTypeListenKeyExpired is the type of the event sent when the
+	// listen key has expired
+	userDataEventTypeListenKeyExpired UserDataEventType = "listenKeyExpired"
+)
+
+// ExecutionType is the type of execution on an order
+type ExecutionType string
+
+const (
+	// ExecutionTypeNew is the execution type of an order accepted by the engine
+	ExecutionTypeNew ExecutionType = "NEW"
+	// ExecutionTypeCanceled is the execution type of an order canceled by the user
+	ExecutionTypeCanceled ExecutionType = "CANCELED"
+	// ExecutionTypeReplaced is the execution type of a replaced order
+	ExecutionTypeReplaced ExecutionType = "REPLACED"
+	// ExecutionTypeRejected is the execution type of an order rejected by the engine
+	ExecutionTypeRejected ExecutionType = "REJECTED"
+	// ExecutionTypeTrade is the execution type of an order that has been (partially) filled
+	ExecutionTypeTrade ExecutionType = "TRADE"
+	// ExecutionTypeExpired is the execution type of an order that has expired
+	ExecutionTypeExpired ExecutionType = "EXPIRED"
+)
+
+// ExecutionReport represents an update on an order
+type ExecutionReport struct {
+	EventTime                time.Time
+	Symbol                   string
+	ClientOrderID            string
+	OriginalClientOrderID    string
+	Side                     OrderSide
+	Type                     OrderType
+	TimeInForce              TimeInForce
+	Quantity                 float64
+	Price                    float64
+	StopPrice                float64
+	ExecutionType            ExecutionType
+	Status                   OrderStatus
+	RejectReason             string
+	OrderID                  int64
+	LastExecutedQuantity     float64
+	CumulativeFilledQuantity float64
+	LastExecutedPrice        float64
+	Commission               float64
+	CommissionAsset          string
+	TransactionTime          time.Time
+	TradeID                  int64
+	IsMaker                  bool
+	OrderCreationTime        time.Time
+	CumulativeQuoteQuantity  float64
+	Exact                    ExecutionReportDecimals
+}
+
+// ExecutionReportDecimals are the exact values of an execution report, as
+// sent by Binance
+type ExecutionReportDecimals struct {
+	Quantity                 Decimal
+	Price                    Decimal
+	StopPrice                Decimal
+	LastExecutedQuantity     Decimal
+	CumulativeFilledQuantity Decimal
+	LastExecutedPrice        Decimal
+	Commission               Decimal
+	CumulativeQuoteQuantity  Decimal
+}
+
+// AccountPosition represents the new balances of the assets that changed on
+// the account
+type AccountPosition struct {
+	EventTime      time.Time
+	LastUpdateTime time.Time
+	Balances       []Balance
+	// ExactBalances are the balances with their exact amounts, in the same
+	// order
+	ExactBalances []ExactBalance
+}
+
+// BalanceUpdate represents a change on an asset balance due to a deposit, a
+// withdrawal or a transfer
+type BalanceUpdate struct {
+	EventTime time.Time
+	Asset     string
+	Delta     float64
+	ClearTime time.Time
+	Exact     BalanceUpdateDecimals
+}
+
+// BalanceUpdateDecimals are the exact values of a balance update, as sent by
+// Binance
+type BalanceUpdateDecimals struct {
+	Delta Decimal
+}
+
+// UserDataEvent is an event coming from the user data stream, only the field
+// corresponding to its type is set
+type UserDataEvent struct {
+	Type            UserDataEventType
+	ExecutionReport *ExecutionReport
+	AccountPosition *AccountPosition
+	BalanceUpdate   *BalanceUpdate
+}
+
+// UserDataStreamService is the real service for user data stream
+type UserDataStreamService struct {
+	client         *binance.Client
+	wsBaseURL      string
+	proxy          func(*http.Request) (*url.URL, error)
+	keepAlive      time.Duration
+	reconnectDelay time.Duration
+	errHandler     func(error)
+	metrics        MetricsInterface
+	tracer         TracerInterface
+}
+
+// Do will start the user data stream and return the channel where the events
+// will be sent. The stream is kept alive and reconnected until the context is
+// done, then the channel is closed.
+func (s *UserDataStreamService) Do(ctx context.Context) (<-chan UserDataEvent, error) {
+	if s.keepAlive <= 0 {
+		return nil, ErrUserDataInvalidKeepAlive
+	}
+
+	listenKey, conn, err := s.connect(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan UserDataEvent, userDataEventsBufferSize)
+	go s.run(ctx, listenKey, conn, events)
+	return events, nil
+}
+
+// KeepAlive will specify the interval between two listen key keepalives, it
+// should be positive
+func (s *UserDataStreamService) KeepAlive(interval time.Duration) UserDataStreamServiceInterface {
+	s.keepAlive = interval
+	return s
+}
+
+// ErrorHandler will specify a function that will be called with every error
+// happening on the stream after it has started (keepalive, connection or
+// decoding errors). These errors are not fatal as the stream will reconnect.
+func (s *UserDataStreamService) ErrorHandler(handler func(error)) UserDataStreamServiceInterface {
+	s.errHandler = handler
+	return s
+}
+
+func (s *UserDataStreamService) handleError(err error) {
+	if s.errHandler != nil {
+		s.errHandler(err)
+	}
+}
+
+func (s *UserDataStreamService) addRetry() {
+	if s.metrics != nil {
+		s.metrics.AddRetry(userDataStreamName)
+	}
+}
+
+func (s *UserDataStreamService) addStreamReconnect() {
+	if s.metrics != nil {
+		s.metrics.AddStreamReconnect(userDataStreamName)
+	}
+}
+
+// connect will get a listen key and connect to the stream, attempt is the
+// number of retries since the last connection
+func (s *UserDataStreamService) connect(ctx context.Context, attempt int) (listenKey string, conn *websocket.Conn, err error) {
+	ctx, span := startSpan(ctx, s.tracer, SpanUserDataStreamConnect,
+		Attribute{Key: AttributeRetryAttempt, Value: attempt})
+	defer func() { span.end(err) }()
+
+	// Get a listen key
+	err = call(ctx, s.tracer, func(ctx context.Context) (err error) {
+		listenKey, err = s.client.NewStartUserStreamService().Do(ctx)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Connect to websocket
+	conn, err = s.dial(ctx, fmt.Sprintf("%s/%s", s.wsBaseURL, listenKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return listenKey, conn, nil
+}
+
+// dial will connect to the websocket, the connection and its handshake being
+// interrupted when the context is done. The websocket dialer has no context
+// support, so the underlying connection is closed on the context end.
+func (s *UserDataStreamService) dial(ctx context.Context, url string) (*websocket.Conn, error) {
+	var mutex sync.Mutex
+	var netConn net.Conn
+	dialer := websocket.Dialer{
+		Proxy:            s.proxy,
+		HandshakeTimeout: userDataHandshakeTimeout,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			d := net.Dialer{Timeout: userDataHandshakeTimeout}
+			c, err := d.DialContext(ctx, network, addr)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			netConn = c
+			return c, err
+		},
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			mutex.Lock()
+			defer mutex.Unlock()
+			if netConn != nil {
+				_ = netConn.Close()
+			}
+		case <-done:
+		}
+	}()
+
+	conn, _, err := dialer.Dial(url, nil)
+	close(done)
+	<-stopped
+
+	// The connection may have been closed after the handshake
+	if ctx.Err() != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, ctx.Err()
+	}
+	return conn, err
+}
+
+func (s *UserDataStreamService) reconnect(ctx context.Context) (string, *websocket.Conn, bool) {
+	for attempt := 0; ; attempt++ {
+		listenKey, conn, err := s.connect(ctx, attempt)
+		if err == nil {
+			return listenKey, conn, true
+		}
+		s.handleError(err)
+
+		select {
+		case <-ctx.Done():
+			return "", nil, false
+		case <-time.After(s.reconnectDelay):
+			s.addRetry()
+		}
+	}
+}
+
+func (s *UserDataStreamService) run(ctx context.Context, listenKey string, conn *websocket.Conn, events chan<- UserDataEvent) {
+	defer close(events)
+
+	ticker := time.NewTicker(s.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		done := make(chan struct{})
+		messages, readErr := readWebSocket(conn, done)
+
+		reconnect := false
+		for !reconnect {
+			select {
+			case <-ctx.Done():
+				close(done)
+				_ = conn.Close()
+				s.closeListenKey(listenKey)
+				return
+			case <-ticker.C:
+				err := s.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+				if err != nil {
+					s.handleError(err)
+					reconnect = true
+				}
+			case err := <-readErr:
+				s.handleError(err)
+				reconnect = true
+			case msg := <-messages:
+				e, err := decodeUserDataEvent(msg)
+				if err != nil {
+					s.handleError(err)
+					continue
+				}
+
+				if e.Type == userDataEventTypeListenKeyExpired {
+					s.handleError(ErrUserDataListenKeyExpired)
+					reconnect = true
+					continue
+				}
+
+				select {
+				case events <- e:
+				case <-ctx.Done():
+				}
+			}
+		}
+
+		// Reconnect to the stream with a new listen key
+		close(done)
+		_ = conn.Close()
+		s.closeListenKey(listenKey)
+		s.addStreamReconnect()
+		var ok bool
+		if listenKey, conn, ok = s.reconnect(ctx); !ok {
+			return
+		}
+	}
+}
+
+func (s *UserDataStreamService) closeListenKey(listenKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.client.NewCloseUserStreamService().ListenKey(listenKey).Do(ctx); err != nil {
+		s.handleError(err)
+	}
+}
+
+// readWebSocket will read messages from the connection until it fails, which
+// happens when the connection is closed, or until done is closed
+func readWebSocket(conn *websocket.Conn, done <-chan struct{}) (<-chan []byte, <-chan error) {
+	messages := make(chan []byte)
+	errs := make(chan error, 1)
+
+	go func() {
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+type rawUserDataEvent struct {
+	Type UserDataEventType `json:"e"`
+	Time int64             `json:"E"`
+}
+
+// rawExecutionReport contains every fields of the execution report, as
+// fields with the same letter in another case would be wrongly unmarshaled
+// into the declared ones otherwise
+type rawExecutionReport struct {
+	Symbol                   string `json:"s"`
+	ClientOrderID            string `json:"c"`
+	Side                     string `json:"S"`
+	Type                     string `json:"o"`
+	TimeInForce              string `json:"f"`
+	Quantity                 string `json:"q"`
+	Price                    string `json:"p"`
+	StopPrice                string `json:"P"`
+	OriginalClientOrderID    string `json:"C"`
+	ExecutionType            string `json:"x"`
+	Status                   string `json:"X"`
+	RejectReason             string `json:"r"`
+	OrderID                  int64  `json:"i"`
+	LastExecutedQuantity     string `json:"l"`
+	CumulativeFilledQuantity string `json:"z"`
+	LastExecutedPrice        string `json:"L"`
+	Commission               string `json:"n"`
+	CommissionAsset          string `json:"N"`
+	TransactionTime          int64  `json:"T"`
+	TradeID                  int64  `json:"t"`
+	IsMaker                  bool   `json:"m"`
+	OrderCreationTime        int64  `json:"O"`
+	CumulativeQuoteQuantity  string `json:"Z"`
+	IcebergQuantity          string `json:"F"`
+	Ignore                   int64  `json:"I"`
+	IgnoreBool               bool   `json:"M"`
+	QuoteOrderQuantity       string `json:"Q"`
+}
+
+type rawAccountPosition struct {
+	LastUpdateTime int64 `json:"u"`
+	Balances       []struct {
+		Asset  string `json:"a"`
+		Free   string `json:"f"`
+		Locked string `json:"l"`
+	} `json:"B"`
+}
+
+type rawBalanceUpdate struct {
+	Asset     string `json:"a"`
+	Delta     string `json:"d"`
+	ClearTime int64  `json:"T"`
+}
+
+// decodeUserDataEvent will decode a message from user data stream
+func decodeUserDataEvent(msg []byte) (UserDataEvent, error) {
+	var raw rawUserDataEvent
+	if err := json.Unmarshal(msg, &raw); err != nil {
+		return UserDataEvent{}, err
+	}
+
+	e := UserDataEvent{Type: raw.Type}
+	eventTime := timeFromBinance(raw.Time)
+
+	switch raw.Type {
+	case UserDataEventTypeExecutionReport:
+		var r rawExecutionReport
+		if err := json.Unmarshal(msg, &r); err != nil {
+			return UserDataEvent{}, err
+		}
+
+		d, f, err := parseDecimals(r.Quantity, r.Price, r.StopPrice, r.LastExecutedQuantity,
+			r.CumulativeFilledQuantity, r.LastExecutedPrice, r.Commission, r.CumulativeQuoteQuantity)
+		if err != nil {
+			return UserDataEvent{}, err
+		}
+
+		e.ExecutionReport = &ExecutionReport{
+			EventTime:                eventTime,
+			Symbol:                   r.Symbol,
+			ClientOrderID:            r.ClientOrderID,
+			OriginalClientOrderID:    r.OriginalClientOrderID,
+			Side:                     OrderSide(r.Side),
+			Type:                     OrderType(r.Type),
+			TimeInForce:              TimeInForce(r.TimeInForce),
+			Quantity:                 f[0],
+			Price:                    f[1],
+			StopPrice:                f[2],
+			ExecutionType:            ExecutionType(r.ExecutionType),
+			Status:                   OrderStatus(r.Status),
+			RejectReason:             r.RejectReason,
+			OrderID:                  r.OrderID,
+			LastExecutedQuantity:     f[3],
+			CumulativeFilledQuantity: f[4],
+			LastExecutedPrice:        f[5],
+			Commission:               f[6],
+			CommissionAsset:          r.CommissionAsset,
+			TransactionTime:          timeFromBinance(r.TransactionTime),
+			TradeID:                  r.TradeID,
+			IsMaker:                  r.IsMaker,
+			OrderCreationTime:        timeFromBinance(r.OrderCreationTime),
+			CumulativeQuoteQuantity:  f[7],
+			Exact: ExecutionReportDecimals{
+				Quantity:                 d[0],
+				Price:                    d[1],
+				StopPrice:                d[2],
+				LastExecutedQuantity:     d[3],
+				CumulativeFilledQuantity: d[4],
+				LastExecutedPrice:        d[5],
+				Commission:               d[6],
+				CumulativeQuoteQuantity:  d[7],
+			},
+		}
+	case UserDataEventTypeAccountPosition:
+		var r rawAccountPosition
+		if err := json.Unmarshal(msg, &r); err != nil {
+			return UserDataEvent{}, err
+		}
+
+		p := &AccountPosition{
+			EventTime:      eventTime,
+			LastUpdateTime: timeFromBinance(r.LastUpdateTime),
+			Balances:       make([]Balance, len(r.Balances)),
+			ExactBalances:  make([]ExactBalance, len(r.Balances)),
+		}
+		for i, b := range r.Balances {
+			balance, err := exactBalanceFromBinance(binance.Balance{Asset: b.Asset, Free: b.Free, Locked: b.Locked})
+			if err != nil {
+				return UserDataEvent{}, err
+			}
+			p.Balances[i] = balance.Balance
+			p.ExactBalances[i] = balance
+		}
+		e.AccountPosition = p
+	case UserDataEventTypeBalanceUpdate:
+		var r rawBalanceUpdate
+		if err := json.Unmarshal(msg, &r); err != nil {
+			return UserDataEvent{}, err
+		}
+
+		d, f, err := parseDecimals(r.Delta)
+		if err != nil {
+			return UserDataEvent{}, err
+		}
+
+		e.BalanceUpdate = &BalanceUpdate{
+			EventTime: eventTime,
+			Asset:     r.Asset,
+			Delta:     f[0],
+			ClearTime: timeFromBinance(r.ClearTime),
+			Exact:     BalanceUpdateDecimals{Delta: d[0]},
+		}
+	case userDataEventTypeListenKeyExpired:
+	default:
+		return UserDataEvent{}, fmt.Errorf("user data stream error: unknown event type %q", raw.Type)
+	}
+
+	return e, nil
+}
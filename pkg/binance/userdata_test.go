@@ -0,0 +1,424 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	testExecutionReport = `{"e":"executionReport","E":1499405658658,"s":"ETHBTC","c":"mUvoqJxFIILMdfAW5iGSOW",` +
+		`"S":"BUY","o":"LIMIT","f":"GTC","q":"1.00000000","p":"0.10264410","P":"0.00000000","F":"0.00000000",` +
+		`"g":-1,"C":"","x":"TRADE","X":"PARTIALLY_FILLED","r":"NONE","i":4293153,"l":"0.50000000",` +
+		`"z":"0.50000000","L":"0.10264410","n":"0.00050000","N":"ETH","T":1499405658657,"t":42,"I":8641984,` +
+		`"w":true,"m":true,"M":false,"O":1499405658600,"Z":"0.05132205","Y":"0.05132205","Q":"2.00000000"}`
+	testAccountPosition = `{"e":"outboundAccountPosition","E":1564034571105,"u":1564034571073,` +
+		`"B":[{"a":"ETH","f":"10000.000000","l":"1.000000"}]}`
+	testBalanceUpdate    = `{"e":"balanceUpdate","E":1573200697110,"a":"BTC","d":"-100.00000000","T":1573200697068}`
+	testListenKeyExpired = `{"e":"listenKeyExpired","E":1576653824250}`
+)
+
+func TestDecodeUserDataEvent_ExecutionReport(t *testing.T) {
+	e, err := decodeUserDataEvent([]byte(testExecutionReport))
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if e.Type != UserDataEventTypeExecutionReport || e.ExecutionReport == nil {
+		t.Fatal("Event is not an execution report:", e)
+	}
+
+	expected := ExecutionReport{
+		EventTime:                timeFromBinance(1499405658658),
+		Symbol:                   "ETHBTC",
+		ClientOrderID:            "mUvoqJxFIILMdfAW5iGSOW",
+		Side:                     OrderSideBuy,
+		Type:                     OrderTypeLimit,
+		TimeInForce:              TimeInForceGTC,
+		Quantity:                 1,
+		Price:                    0.10264410,
+		ExecutionType:            ExecutionTypeTrade,
+		Status:                   OrderStatusPartiallyFilled,
+		RejectReason:             "NONE",
+		OrderID:                  4293153,
+		LastExecutedQuantity:     0.5,
+		CumulativeFilledQuantity: 0.5,
+		LastExecutedPrice:        0.10264410,
+		Commission:               0.0005,
+		CommissionAsset:          "ETH",
+		TransactionTime:          timeFromBinance(1499405658657),
+		TradeID:                  42,
+		IsMaker:                  true,
+		OrderCreationTime:        timeFromBinance(1499405658600),
+		CumulativeQuoteQuantity:  0.05132205,
+		Exact: ExecutionReportDecimals{
+			Quantity:                 "1.00000000",
+			Price:                    "0.10264410",
+			StopPrice:                "0.00000000",
+			LastExecutedQuantity:     "0.50000000",
+			CumulativeFilledQuantity: "0.50000000",
+			LastExecutedPrice:        "0.10264410",
+			Commission:               "0.00050000",
+			CumulativeQuoteQuantity:  "0.05132205",
+		},
+	}
+
+	if *e.ExecutionReport != expected {
+		t.Error("Execution report is not decoded correctly:", expected, *e.ExecutionReport)
+	}
+}
+
+func TestDecodeUserDataEvent_AccountPosition(t *testing.T) {
+	e, err := decodeUserDataEvent([]byte(testAccountPosition))
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if e.Type != UserDataEventTypeAccountPosition || e.AccountPosition == nil {
+		t.Fatal("Event is not an account position:", e)
+	}
+
+	p := e.AccountPosition
+	if !p.LastUpdateTime.Equal(timeFromBinance(1564034571073)) {
+		t.Error("Last update time is not decoded correctly:", p.LastUpdateTime)
+	}
+
+	expected := Balance{Asset: "ETH", Free: 10000, Locked: 1}
+	if len(p.Balances) != 1 || p.Balances[0] != expected {
+		t.Error("Balances are not decoded correctly:", p.Balances)
+	}
+
+	exact := BalanceDecimals{Free: "10000.000000", Locked: "1.000000"}
+	if len(p.ExactBalances) != 1 || p.ExactBalances[0].Balance != expected || p.ExactBalances[0].Exact != exact {
+		t.Error("Exact balances are not decoded correctly:", p.ExactBalances)
+	}
+}
+
+func TestDecodeUserDataEvent_BalanceUpdate(t *testing.T) {
+	e, err := decodeUserDataEvent([]byte(testBalanceUpdate))
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if e.Type != UserDataEventTypeBalanceUpdate || e.BalanceUpdate == nil {
+		t.Fatal("Event is not a balance update:", e)
+	}
+
+	b := e.BalanceUpdate
+	if b.Asset != "BTC" || b.Delta != -100 || !b.ClearTime.Equal(timeFromBinance(1573200697068)) {
+		t.Error("Balance update is not decoded correctly:", b)
+	}
+
+	if b.Exact.Delta != "-100.00000000" {
+		t.Error("Balance update delta should be exact but is", b.Exact.Delta)
+	}
+}
+
+func TestDecodeUserDataEvent_ExactSmallValues(t *testing.T) {
+	msg := `{"e":"balanceUpdate","E":1573200697110,"a":"BTC","d":"0.00000001","T":1573200697068}`
+	e, err := decodeUserDataEvent([]byte(msg))
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if d := e.BalanceUpdate.Exact.Delta; d.String() != "0.00000001" || d.Rat().Cmp(big.NewRat(1, 100000000)) != 0 {
+		t.Error("Delta should be exactly 0.00000001 but is", d)
+	}
+}
+
+func TestDecodeUserDataEvent_Errors(t *testing.T) {
+	msgs := []string{
+		`not json`,
+		`{"e":"unknown","E":1}`,
+		`{"e":"balanceUpdate","E":1,"a":"BTC","d":"error","T":1}`,
+		`{"e":"outboundAccountPosition","E":1,"u":1,"B":[{"a":"ETH","f":"error","l":"0"}]}`,
+		`{"e":"executionReport","E":1,"q":"error"}`,
+	}
+
+	for i, msg := range msgs {
+		if _, err := decodeUserDataEvent([]byte(msg)); err == nil {
+			t.Error("There should be an error on message", i)
+		}
+	}
+}
+
+// userDataTestServer is a stand-in for Binance REST and websocket endpoints
+// used by the user data stream
+type userDataTestServer struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	calls       map[string]int
+	connections int
+	messages    [][]string
+}
+
+func newUserDataTestServer(messages ...[]string) *userDataTestServer {
+	s := &userDataTestServer{
+		calls:    make(map[string]int),
+		messages: messages,
+	}
+
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/userDataStream" {
+			s.mutex.Lock()
+			s.calls[r.Method]++
+			s.mutex.Unlock()
+			fmt.Fprint(w, `{"listenKey":"testkey"}`)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		s.mutex.Lock()
+		var msgs []string
+		if s.connections < len(s.messages) {
+			msgs = s.messages[s.connections]
+		}
+		s.connections++
+		s.mutex.Unlock()
+
+		for _, msg := range msgs {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		// Wait for the client to close the connection
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return s
+}
+
+func (s *userDataTestServer) callsCount(method string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls[method]
+}
+
+func (s *userDataTestServer) service() *UserDataStreamService {
+	service := New("key", "secret",
+		WithBaseURL(s.URL),
+		WithWebSocketBaseURL("ws"+strings.TrimPrefix(s.URL, "http")+"/ws"))
+
+	uds := service.NewUserDataStreamService().(*UserDataStreamService)
+	uds.reconnectDelay = time.Millisecond
+	return uds
+}
+
+func receiveUserDataEvent(t *testing.T, events <-chan UserDataEvent) UserDataEvent {
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("Events channel should not be closed")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("No event received")
+	}
+	return UserDataEvent{}
+}
+
+func TestUserDataStreamDo(t *testing.T) {
+	server := newUserDataTestServer([]string{testExecutionReport, testAccountPosition, testBalanceUpdate})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := server.service().Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	types := []UserDataEventType{
+		UserDataEventTypeExecutionReport,
+		UserDataEventTypeAccountPosition,
+		UserDataEventTypeBalanceUpdate,
+	}
+	for i, et := range types {
+		if e := receiveUserDataEvent(t, events); e.Type != et {
+			t.Error("Event", i, "should be", et, "but is", e.Type)
+		}
+	}
+
+	// Check that the channel is closed and the listen key deleted when done
+	cancel()
+	for range events {
+	}
+
+	if server.callsCount(http.MethodDelete) != 1 {
+		t.Error("Listen key should have been closed")
+	}
+}
+
+func TestUserDataStreamDo_KeepAlive(t *testing.T) {
+	server := newUserDataTestServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := server.service().KeepAlive(10 * time.Millisecond).Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for server.callsCount(http.MethodPut) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Listen key should have been kept alive")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUserDataStreamDo_InvalidKeepAlive(t *testing.T) {
+	server := newUserDataTestServer()
+	defer server.Close()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err := server.service().KeepAlive(interval).Do(context.TODO())
+		if err != ErrUserDataInvalidKeepAlive {
+			t.Error("There should be an error with an interval of", interval, "but there is", err)
+		}
+	}
+
+	if n := server.callsCount(http.MethodPost); n != 0 {
+		t.Error("No listen key should have been requested but there is", n)
+	}
+}
+
+func TestUserDataStreamDo_ReconnectOnExpiry(t *testing.T) {
+	server := newUserDataTestServer(
+		[]string{testListenKeyExpired},
+		[]string{testBalanceUpdate},
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mutex sync.Mutex
+	var errs []error
+	events, err := server.service().ErrorHandler(func(err error) {
+		mutex.Lock()
+		errs = append(errs, err)
+		mutex.Unlock()
+	}).Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if e := receiveUserDataEvent(t, events); e.Type != UserDataEventTypeBalanceUpdate {
+		t.Error("Event should be a balance update but is", e.Type)
+	}
+
+	if server.callsCount(http.MethodPost) != 2 {
+		t.Error("Listen key should have been requested twice, but was", server.callsCount(http.MethodPost))
+	}
+
+	if server.callsCount(http.MethodDelete) != 1 {
+		t.Error("Expired listen key should have been closed, but was closed", server.callsCount(http.MethodDelete), "times")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(errs) == 0 || errs[0] != ErrUserDataListenKeyExpired {
+		t.Error("Listen key expiry should have been reported:", errs)
+	}
+}
+
+func TestUserDataStreamDo_HandshakeCanceled(t *testing.T) {
+	server := newUserDataTestServer()
+	defer server.Close()
+
+	// Accept connections without ever answering the websocket handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	uds := server.service()
+	uds.wsBaseURL = "ws://" + listener.Addr().String() + "/ws"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := uds.Do(ctx); err == nil {
+		t.Error("There should be an error when the handshake is canceled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Error("The handshake should be interrupted by the context, but took", elapsed)
+	}
+}
+
+func TestUserDataStreamDo_ListenKeyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`)
+	}))
+	defer server.Close()
+
+	service := New("key", "secret").(*Service)
+	service.client.BaseURL = server.URL
+
+	if _, err := service.NewUserDataStreamService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on listen key creation")
+	}
+}
+
+func TestUserDataStreamDo_ReconnectMetrics(t *testing.T) {
+	server := newUserDataTestServer(
+		[]string{testListenKeyExpired},
+		[]string{testBalanceUpdate},
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := newRecordingMetrics()
+	uds := server.service()
+	uds.metrics = m
+
+	events, err := uds.Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	receiveUserDataEvent(t, events)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.reconnects != 1 {
+		t.Error("There should be 1 reconnection but there is", m.reconnects)
+	}
+}
@@ -0,0 +1,188 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/binance.go/pkg/adapters"
+)
+
+// ErrInvalidCandleSticks is the error wrapped by ValidationError
+var ErrInvalidCandleSticks = errors.New("candlestick error: invalid values")
+
+// ValidationPolicy is the policy applied by candlestick services to
+// candlesticks that fail validation
+type ValidationPolicy string
+
+const (
+	// ValidationPolicyNone is the policy that does not validate candlesticks
+	ValidationPolicyNone ValidationPolicy = ""
+	// ValidationPolicyReject is the policy that rejects the whole response
+	// with a *ValidationError if a candlestick is invalid
+	ValidationPolicyReject ValidationPolicy = "REJECT"
+	// ValidationPolicyDrop is the policy that drops invalid candlesticks
+	ValidationPolicyDrop ValidationPolicy = "DROP"
+	// ValidationPolicyFlag is the policy that keeps invalid candlesticks and
+	// reports their violations in ExactCandleStick.Violations
+	ValidationPolicyFlag ValidationPolicy = "FLAG"
+)
+
+// ValidationRule is a rule that a candlestick can violate
+type ValidationRule string
+
+const (
+	// ValidationRuleOHLC is violated when the high is not the highest value or
+	// the low is not the lowest value
+	ValidationRuleOHLC ValidationRule = "OHLC"
+	// ValidationRulePrice is violated when a price is not strictly positive
+	ValidationRulePrice ValidationRule = "PRICE"
+	// ValidationRuleVolume is violated when the volume is negative
+	ValidationRuleVolume ValidationRule = "VOLUME"
+	// ValidationRuleTime is violated when the time is not after the previous
+	// candlestick time
+	ValidationRuleTime ValidationRule = "TIME"
+	// ValidationRuleAlignment is violated when the time is not aligned on the
+	// candlestick period
+	ValidationRuleAlignment ValidationRule = "ALIGNMENT"
+)
+
+// Violation is a validation rule violated by a candlestick
+type Violation struct {
+	Symbol  string
+	Time    time.Time
+	Rule    ValidationRule
+	Message string
+}
+
+// String will return a readable description of the violation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at %s: %s: %s", v.Symbol, v.Time.Format(time.RFC3339Nano), v.Rule, v.Message)
+}
+
+// ValidationError is the error returned when candlesticks are rejected by
+// the validation
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error will return the error message, with the first violation
+func (e *ValidationError) Error() string {
+	if len(e.Violations) == 0 {
+		return ErrInvalidCandleSticks.Error()
+	}
+	return fmt.Sprintf("%s: %d violation(s), first is %s", ErrInvalidCandleSticks, len(e.Violations), e.Violations[0])
+}
+
+// Unwrap will return ErrInvalidCandleSticks
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidCandleSticks
+}
+
+// ValidateCandleSticks will check chronologically ordered candlesticks of a
+// symbol and period, and return their violations. The alignment is not checked
+// if the period is not supported by Binance, i.e. 0.
+func ValidateCandleSticks(symbol string, period int64, cs []ExactCandleStick) []Violation {
+	violations := make([]Violation, 0)
+	for _, v := range validateCandleSticks(symbol, period, cs) {
+		violations = append(violations, v...)
+	}
+	return violations
+}
+
+// Apply will validate chronologically ordered candlesticks of a symbol and
+// period according to the policy
+func (p ValidationPolicy) Apply(symbol string, period int64, cs []ExactCandleStick) ([]ExactCandleStick, error) {
+	if p == ValidationPolicyNone {
+		return cs, nil
+	}
+
+	violations := validateCandleSticks(symbol, period, cs)
+	switch p {
+	case ValidationPolicyReject:
+		all := make([]Violation, 0)
+		for _, v := range violations {
+			all = append(all, v...)
+		}
+		if len(all) > 0 {
+			return nil, &ValidationError{Violations: all}
+		}
+		return cs, nil
+	case ValidationPolicyDrop:
+		kept := make([]ExactCandleStick, 0, len(cs))
+		for i, c := range cs {
+			if len(violations[i]) == 0 {
+				kept = append(kept, c)
+			}
+		}
+		return kept, nil
+	case ValidationPolicyFlag:
+		for i := range cs {
+			if len(violations[i]) > 0 {
+				cs[i].Violations = violations[i]
+			}
+		}
+		return cs, nil
+	default:
+		return nil, fmt.Errorf("candlestick error: unknown validation policy %q", p)
+	}
+}
+
+// validateCandleSticks will return the violations of each candlestick
+func validateCandleSticks(symbol string, period int64, cs []ExactCandleStick) [][]Violation {
+	violations := make([][]Violation, len(cs))
+	for i, c := range cs {
+		var previous *ExactCandleStick
+		if i > 0 {
+			previous = &cs[i-1]
+		}
+
+		violations[i] = validateCandleStick(c, previous, period)
+		for j := range violations[i] {
+			violations[i][j].Symbol = symbol
+			violations[i][j].Time = c.Time
+		}
+	}
+	return violations
+}
+
+func validateCandleStick(c ExactCandleStick, previous *ExactCandleStick, period int64) []Violation {
+	violations := make([]Violation, 0)
+
+	if c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0 {
+		violations = append(violations, Violation{
+			Rule:    ValidationRulePrice,
+			Message: fmt.Sprintf("prices should be positive (O:%v H:%v L:%v C:%v)", c.Open, c.High, c.Low, c.Close),
+		})
+	}
+
+	if c.High < c.Open || c.High < c.Close || c.Low > c.Open || c.Low > c.Close {
+		violations = append(violations, Violation{
+			Rule:    ValidationRuleOHLC,
+			Message: fmt.Sprintf("low and high should bound open and close (O:%v H:%v L:%v C:%v)", c.Open, c.High, c.Low, c.Close),
+		})
+	}
+
+	if volume := c.Exact.Volume.Rat(); volume != nil && volume.Sign() < 0 {
+		violations = append(violations, Violation{
+			Rule:    ValidationRuleVolume,
+			Message: fmt.Sprintf("volume should not be negative (%s)", c.Exact.Volume),
+		})
+	}
+
+	if previous != nil && !c.Time.After(previous.Time) {
+		violations = append(violations, Violation{
+			Rule:    ValidationRuleTime,
+			Message: fmt.Sprintf("time should be after previous one (%s)", previous.Time.Format(time.RFC3339Nano)),
+		})
+	}
+
+	if aligned, err := adapters.IsCandleTime(c.Time, period); err == nil && !aligned {
+		violations = append(violations, Violation{
+			Rule:    ValidationRuleAlignment,
+			Message: fmt.Sprintf("time should be aligned on period %ds", period),
+		})
+	}
+
+	return violations
+}
@@ -0,0 +1,118 @@
+package binance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func testValidationCandleSticks() []ExactCandleStick {
+	return []ExactCandleStick{
+		// Valid
+		{CandleStick: models.CandleStick{Time: time.Unix(0, 0), Open: 1, High: 2, Low: 0.5, Close: 1.5}},
+		// High below open
+		{CandleStick: models.CandleStick{Time: time.Unix(60, 0), Open: 1, High: 0.9, Low: 0.5, Close: 0.8}},
+		// Zero price and negative volume
+		{
+			CandleStick: models.CandleStick{Time: time.Unix(120, 0), Open: 0, High: 2, Low: 0, Close: 1},
+			Exact:       CandleStickDecimals{Volume: "-1"},
+		},
+		// Duplicate time
+		{CandleStick: models.CandleStick{Time: time.Unix(120, 0), Open: 1, High: 1, Low: 1, Close: 1}},
+		// Not aligned
+		{CandleStick: models.CandleStick{Time: time.Unix(190, 0), Open: 1, High: 1, Low: 1, Close: 1}},
+		// Valid
+		{CandleStick: models.CandleStick{Time: time.Unix(240, 0), Open: 1, High: 1, Low: 1, Close: 1}},
+	}
+}
+
+func TestValidateCandleSticks(t *testing.T) {
+	violations := ValidateCandleSticks("BTCUSDT", models.M1, testValidationCandleSticks())
+
+	expected := []struct {
+		Time int64
+		Rule ValidationRule
+	}{
+		{60, ValidationRuleOHLC},
+		{120, ValidationRulePrice},
+		{120, ValidationRuleVolume},
+		{120, ValidationRuleTime},
+		{190, ValidationRuleAlignment},
+	}
+
+	if len(violations) != len(expected) {
+		t.Fatal("There should be", len(expected), "violations but there is", len(violations), violations)
+	}
+	for i, e := range expected {
+		v := violations[i]
+		if v.Symbol != "BTCUSDT" || v.Time.Unix() != e.Time || v.Rule != e.Rule {
+			t.Error("Violation", i, "should be", e, "but is", v)
+		}
+	}
+}
+
+func TestValidateCandleSticks_WeeklyAlignment(t *testing.T) {
+	monday := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)
+	cs := []ExactCandleStick{
+		{CandleStick: models.CandleStick{Time: monday, Open: 1, High: 1, Low: 1, Close: 1}},
+		{CandleStick: models.CandleStick{Time: monday.Add(8 * 24 * time.Hour), Open: 1, High: 1, Low: 1, Close: 1}},
+	}
+
+	violations := ValidateCandleSticks("BTCUSDT", models.W1, cs)
+	if len(violations) != 1 || !violations[0].Time.Equal(cs[1].Time) || violations[0].Rule != ValidationRuleAlignment {
+		t.Error("There should be only an alignment violation on the second candlestick but there is", violations)
+	}
+}
+
+func TestValidationPolicy_Reject(t *testing.T) {
+	_, err := ValidationPolicyReject.Apply("BTCUSDT", models.M1, testValidationCandleSticks())
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidCandleSticks) {
+		t.Fatal("There should be a validation error but there is", err)
+	}
+	if len(verr.Violations) != 5 {
+		t.Error("There should be 5 violations but there is", len(verr.Violations))
+	}
+}
+
+func TestValidationPolicy_Drop(t *testing.T) {
+	cs, err := ValidationPolicyDrop.Apply("BTCUSDT", models.M1, testValidationCandleSticks())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if len(cs) != 2 || cs[0].Time.Unix() != 0 || cs[1].Time.Unix() != 240 {
+		t.Error("Only valid candlesticks should be kept but there is", cs)
+	}
+}
+
+func TestValidationPolicy_Flag(t *testing.T) {
+	cs, err := ValidationPolicyFlag.Apply("BTCUSDT", models.M1, testValidationCandleSticks())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	counts := []int{0, 1, 2, 1, 1, 0}
+	if len(cs) != len(counts) {
+		t.Fatal("There should be", len(counts), "candlesticks but there is", len(cs))
+	}
+	for i, c := range counts {
+		if len(cs[i].Violations) != c {
+			t.Error("Candlestick", i, "should have", c, "violations but has", cs[i].Violations)
+		}
+	}
+}
+
+func TestValidationPolicy_None(t *testing.T) {
+	cs, err := ValidationPolicyNone.Apply("BTCUSDT", models.M1, testValidationCandleSticks())
+	if err != nil || len(cs) != 6 {
+		t.Error("Candlesticks should not be validated:", len(cs), err)
+	}
+
+	if _, err := ValidationPolicy("error").Apply("BTCUSDT", models.M1, testValidationCandleSticks()); err == nil {
+		t.Error("There should be an error with an unknown policy")
+	}
+}
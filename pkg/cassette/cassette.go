@@ -0,0 +1,333 @@
+// Package cassette provides an HTTP transport that records Binance responses
+// into a file and replays them deterministically afterwards.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted is the value that replaces secrets in recorded interactions
+const Redacted = "REDACTED"
+
+var (
+	// ErrNoInteraction is returned in replay mode when a request has no recorded match
+	ErrNoInteraction = errors.New("cassette: no recorded interaction for request")
+	// ErrInvalidMode is returned when the mode is unknown
+	ErrInvalidMode = errors.New("cassette: invalid mode")
+)
+
+// Mode is the mode of the cassette
+type Mode string
+
+const (
+	// ModeLive will send requests without recording them
+	ModeLive Mode = "live"
+	// ModeRecord will send requests and record them into the cassette file
+	ModeRecord Mode = "record"
+	// ModeReplay will serve recorded responses without sending requests
+	ModeReplay Mode = "replay"
+)
+
+// ParseMode will parse the mode from its string representation
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeLive, ModeRecord, ModeReplay:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidMode, s)
+	}
+}
+
+// ignoredParams are the parameters that change on each request and are not
+// used to match a request with a recorded interaction
+var ignoredParams = []string{"timestamp", "signature", "listenKey"}
+
+// redactedParams are the parameters whose values are never written
+var redactedParams = []string{"signature", "listenKey"}
+
+// redactedFields matches the response JSON fields whose values are never
+// written, the user data stream listen key giving access to the account events
+var redactedFields = regexp.MustCompile(`("listenKey"\s*:\s*)"[^"]*"`)
+
+// Request is a recorded request, with its secrets redacted. Headers are not
+// recorded, so the API key is never written.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is a recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+
+	key    string
+	played bool
+}
+
+// Cassette is an http.RoundTripper recording or replaying interactions
+// depending on its mode
+type Cassette struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mutex        sync.Mutex
+	interactions []*Interaction
+}
+
+// New will create a cassette backed by the file at path. In replay mode, the
+// file is loaded and should exist. The next RoundTripper is used to send the
+// requests in live and record modes, http.DefaultTransport is used if nil.
+func New(path string, mode Mode, next http.RoundTripper) (*Cassette, error) {
+	if _, err := ParseMode(string(mode)); err != nil {
+		return nil, err
+	}
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	c := &Cassette{
+		path: path,
+		mode: mode,
+		next: next,
+	}
+
+	if mode == ModeReplay {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Mode will return the mode of the cassette
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Interactions will return a copy of the interactions recorded or loaded
+func (c *Cassette) Interactions() []Interaction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := make([]Interaction, len(c.interactions))
+	for i, in := range c.interactions {
+		list[i] = *in
+	}
+	return list
+}
+
+// RoundTrip will execute the request depending on the cassette mode
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch c.mode {
+	case ModeRecord:
+		return c.record(req)
+	case ModeReplay:
+		return c.replay(req)
+	default:
+		return c.next.RoundTrip(req)
+	}
+}
+
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.interactions = append(c.interactions, &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Body:   redactParams(body),
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       redactFields(string(resBody)),
+		},
+	})
+
+	return res, c.save()
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := matchKey(req.Method, req.URL.Path, req.URL.RawQuery, body)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, in := range c.interactions {
+		if in.played || in.key != key {
+			continue
+		}
+		in.played = true
+
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, redactURL(req.URL))
+}
+
+// load will load the interactions from the cassette file
+func (c *Cassette) load() error {
+	content, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+
+	var interactions []*Interaction
+	if err := json.Unmarshal(content, &interactions); err != nil {
+		return fmt.Errorf("cassette: %s: %w", c.path, err)
+	}
+
+	for _, in := range interactions {
+		u, err := url.Parse(in.Request.URL)
+		if err != nil {
+			return fmt.Errorf("cassette: %s: %w", c.path, err)
+		}
+		in.key = matchKey(in.Request.Method, u.Path, u.RawQuery, in.Request.Body)
+	}
+
+	c.interactions = interactions
+	return nil
+}
+
+// save will write the interactions into the cassette file. The lock should
+// be held by the caller.
+func (c *Cassette) save() error {
+	content, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// readBody will read the body of the request and restore it to be sent
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// matchKey will return the key used to match a request with a recorded
+// interaction, without the parameters that change on each request
+func matchKey(method, path, query, body string) string {
+	params, _ := url.ParseQuery(query)
+	form, _ := url.ParseQuery(body)
+	for k, v := range form {
+		params[k] = append(params[k], v...)
+	}
+
+	for _, p := range ignoredParams {
+		params.Del(p)
+	}
+
+	return method + " " + path + "?" + params.Encode()
+}
+
+// redactURL will return the URL with its secret parameters redacted
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = redactParams(u.RawQuery)
+	return redacted.String()
+}
+
+// redactParams will redact the secret parameters of an encoded query
+func redactParams(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return Redacted
+	}
+
+	for _, p := range redactedParams {
+		if _, ok := params[p]; ok {
+			params.Set(p, Redacted)
+		}
+	}
+	return params.Encode()
+}
+
+// redactFields will redact the secret fields of a JSON response body
+func redactFields(body string) string {
+	return redactedFields.ReplaceAllString(body, `${1}"`+Redacted+`"`)
+}
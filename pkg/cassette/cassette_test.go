@@ -0,0 +1,160 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/fake"
+	"github.com/cryptellation/binance.go/pkg/mock"
+	"github.com/cryptellation/models.go"
+)
+
+func TestParseMode(t *testing.T) {
+	for _, s := range []string{"live", "record", "replay"} {
+		if m, err := ParseMode(s); err != nil || string(m) != s {
+			t.Error("Mode", s, "should be parsed but there is", m, err)
+		}
+	}
+
+	if _, err := ParseMode("error"); !errors.Is(err, ErrInvalidMode) {
+		t.Error("There should be an invalid mode error but there is", err)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddCandleSticks([]mock.CandleSticks{{
+		Symbol: "BTCUSDT", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Open: 1, High: 2, Low: 0.5, Close: 1.5},
+		},
+	}})
+	srv.Mock().SetBalance("BTC", 1, 0)
+
+	// Record
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	service := binance.New("my-api-key", "my-secret-key", append(srv.Options(), binance.WithTransport(rec))...)
+	if _, err := service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(context.Background()); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if _, err := service.NewAccountService().Do(context.Background()); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	// Check redaction
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if strings.Contains(string(content), "my-api-key") {
+		t.Error("API key should not be recorded")
+	}
+	if !strings.Contains(string(content), "signature="+Redacted) {
+		t.Error("Signature should be redacted")
+	}
+
+	// Replay without server
+	srv.Close()
+	rep, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	service = binance.New("other-key", "other-secret", append(srv.Options(), binance.WithTransport(rep))...)
+	cs, err := service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if len(cs) != 1 || cs[0].Close != 1.5 {
+		t.Error("Replayed candlesticks are not correct:", cs)
+	}
+
+	account, err := service.NewAccountService().Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if b, ok := account.Balance("BTC"); !ok || b.Free != 1 {
+		t.Error("Replayed balance is not correct:", account)
+	}
+
+	// Interactions are only played once
+	_, err = service.NewAccountService().Do(context.Background())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Error("There should be a no interaction error but there is", err)
+	}
+
+	// Unknown request
+	_, err = service.NewCandleStickService().Symbol("ETHUSDT").Period(models.M1).Do(context.Background())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Error("There should be a no interaction error but there is", err)
+	}
+}
+
+func TestRecordAndReplay_ListenKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	// Record
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	client := gobinance.NewClient("my-api-key", "my-secret-key")
+	client.BaseURL = srv.URL
+	client.HTTPClient = &http.Client{Transport: rec}
+
+	listenKey, err := client.NewStartUserStreamService().Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if err := client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(context.Background()); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	// Check redaction
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if strings.Contains(string(content), listenKey) {
+		t.Error("Listen key should not be recorded:", string(content))
+	}
+
+	// Replay with another listen key
+	srv.Close()
+	rep, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	client.HTTPClient = &http.Client{Transport: rep}
+
+	if listenKey, err = client.NewStartUserStreamService().Do(context.Background()); err != nil || listenKey != Redacted {
+		t.Error("Listen key should be replayed redacted but is", listenKey, err)
+	}
+	if err := client.NewKeepaliveUserStreamService().ListenKey("other-key").Do(context.Background()); err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+}
+
+func TestReplayMissingFile(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("There should be an error")
+	}
+}
@@ -0,0 +1,285 @@
+// Package conformance provides a test suite that checks that a ServiceInterface
+// implementation has the same candlesticks semantics as Binance.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+	"github.com/cryptellation/models.go"
+)
+
+// Seeder is the hook that creates the tested service, serving the given
+// candlesticks. It is called once per test.
+type Seeder func(t *testing.T, cs []mock.CandleSticks) binance.ServiceInterface
+
+// Suite is the conformance test suite
+type Suite struct {
+	// Seed is the hook that creates the tested service
+	Seed Seeder
+	// Skip are the names of the tests that should be skipped, for known
+	// differences of the implementation
+	Skip []string
+}
+
+// test is a conformance test
+type test struct {
+	name string
+	run  func(t *testing.T, seed Seeder)
+}
+
+var tests = []test{
+	{name: "Values", run: testValues},
+	{name: "SymbolFiltering", run: testSymbolFiltering},
+	{name: "PeriodFiltering", run: testPeriodFiltering},
+	{name: "Limit", run: testLimit},
+	{name: "DefaultLimit", run: testDefaultLimit},
+	{name: "LimitOverMaximum", run: testLimitOverMaximum},
+	{name: "LatestCandles", run: testLatestCandles},
+	{name: "EndTime", run: testEndTime},
+	{name: "StartTime", run: testStartTime},
+	{name: "TimeWindow", run: testTimeWindow},
+	{name: "Ordering", run: testOrdering},
+	{name: "InvalidPeriod", run: testInvalidPeriod},
+	{name: "UnknownSymbol", run: testUnknownSymbol},
+	{name: "NoSymbol", run: testNoSymbol},
+	{name: "IllegalSymbol", run: testIllegalSymbol},
+	{name: "ValidationAfterLimit", run: testValidationAfterLimit},
+}
+
+// Run will run the conformance suite as subtests of t
+func (s Suite) Run(t *testing.T) {
+	if s.Seed == nil {
+		t.Fatal("There should be a seeding hook")
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range s.Skip {
+				if name == tc.name {
+					t.Skip("Skipped by the implementation")
+				}
+			}
+
+			tc.run(t, s.Seed)
+		})
+	}
+}
+
+// origin is the time of the first generated candlestick
+var origin = time.Unix(1600000000, 0).UTC()
+
+// series will generate count candlesticks from origin, with values depending
+// on their index and on base
+func series(symbol string, period int64, count int, base float64) mock.CandleSticks {
+	cs := mock.CandleSticks{Symbol: symbol, Period: period}
+	for i := 0; i < count; i++ {
+		f := base + float64(i)
+		cs.CandleSticks = append(cs.CandleSticks, models.CandleStick{
+			Time:  origin.Add(time.Duration(period*int64(i)) * time.Second),
+			Open:  f,
+			High:  f + 2,
+			Low:   f - 1,
+			Close: f + 1,
+		})
+	}
+	return cs
+}
+
+func fetch(t *testing.T, s binance.CandleStickServiceInterface) []models.CandleStick {
+	cs, err := s.Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	return cs
+}
+
+func checkCandleSticks(t *testing.T, expected, actual []models.CandleStick) {
+	if len(actual) != len(expected) {
+		t.Fatal("There should be", len(expected), "candlesticks but there is", len(actual))
+	}
+
+	for i := range expected {
+		e, a := expected[i], actual[i]
+		if !e.Time.Equal(a.Time) || e.Open != a.Open || e.High != a.High || e.Low != a.Low || e.Close != a.Close {
+			t.Error("Candlestick", i, "should be", e, "but is", a)
+		}
+	}
+}
+
+func checkAPIError(t *testing.T, err error, code int64) {
+	var apiErr *common.APIError
+	if !errors.As(err, &apiErr) {
+		t.Error("There should be an API error, but there is", err)
+	} else if apiErr.Code != code {
+		t.Error("Error code should be", code, "but is", apiErr.Code)
+	}
+}
+
+func testValues(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.H1, 3, 100)
+	service := seed(t, []mock.CandleSticks{s})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.H1))
+	checkCandleSticks(t, s.CandleSticks, cs)
+}
+
+func testSymbolFiltering(t *testing.T, seed Seeder) {
+	btc := series("BTCUSDT", models.M1, 3, 100)
+	eth := series("ETHUSDT", models.M1, 3, 10)
+	service := seed(t, []mock.CandleSticks{btc, eth})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("ETHUSDT").Period(models.M1))
+	checkCandleSticks(t, eth.CandleSticks, cs)
+}
+
+func testPeriodFiltering(t *testing.T, seed Seeder) {
+	m1 := series("BTCUSDT", models.M1, 3, 100)
+	m5 := series("BTCUSDT", models.M5, 3, 200)
+	service := seed(t, []mock.CandleSticks{m1, m5})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M5))
+	checkCandleSticks(t, m5.CandleSticks, cs)
+}
+
+func testLimit(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, 10, 100)})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Limit(3))
+	if len(cs) != 3 {
+		t.Error("There should be 3 candlesticks but there is", len(cs))
+	}
+}
+
+func testDefaultLimit(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, binance.DefaultCandleStickLimit+10, 100)})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1))
+	if len(cs) != binance.DefaultCandleStickLimit {
+		t.Error("There should be", binance.DefaultCandleStickLimit, "candlesticks but there is", len(cs))
+	}
+}
+
+func testLimitOverMaximum(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, binance.MaxCandleStickLimit+10, 100)})
+
+	_, err := service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Limit(binance.MaxCandleStickLimit + 1).Do(context.Background())
+	checkAPIError(t, err, mock.ErrInvalidLimit.Code)
+}
+
+func testLatestCandles(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 10, 100)
+	service := seed(t, []mock.CandleSticks{s})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Limit(3))
+	checkCandleSticks(t, s.CandleSticks[7:], cs)
+}
+
+func testEndTime(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 10, 100)
+	service := seed(t, []mock.CandleSticks{s})
+
+	// End time is inclusive
+	cs := fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).EndTime(s.CandleSticks[5].Time))
+	checkCandleSticks(t, s.CandleSticks[:6], cs)
+
+	// The latest candlesticks before end time are returned
+	cs = fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).EndTime(s.CandleSticks[5].Time).Limit(2))
+	checkCandleSticks(t, s.CandleSticks[4:6], cs)
+}
+
+func testStartTime(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 10, 100)
+	service := seed(t, []mock.CandleSticks{s})
+
+	// Start time is inclusive
+	cs := fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).StartTime(s.CandleSticks[5].Time))
+	checkCandleSticks(t, s.CandleSticks[5:], cs)
+
+	// The earliest candlesticks after start time are returned
+	cs = fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).StartTime(s.CandleSticks[5].Time).Limit(2))
+	checkCandleSticks(t, s.CandleSticks[5:7], cs)
+}
+
+func testTimeWindow(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 10, 100)
+	service := seed(t, []mock.CandleSticks{s})
+
+	cs := fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).
+		StartTime(s.CandleSticks[2].Time).EndTime(s.CandleSticks[8].Time).Limit(3))
+	checkCandleSticks(t, s.CandleSticks[2:5], cs)
+}
+
+func testOrdering(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 6, 100)
+	later := mock.CandleSticks{Symbol: "BTCUSDT", Period: models.M1, CandleSticks: s.CandleSticks[3:]}
+	earlier := mock.CandleSticks{Symbol: "BTCUSDT", Period: models.M1, CandleSticks: s.CandleSticks[:3]}
+	service := seed(t, []mock.CandleSticks{later, earlier})
+
+	cs := fetch(t, service.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1))
+	checkCandleSticks(t, s.CandleSticks, cs)
+}
+
+func testInvalidPeriod(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, 3, 100)})
+
+	_, err := service.NewCandleStickService().Symbol("BTCUSDT").Period(42).Do(context.Background())
+	checkAPIError(t, err, mock.ErrInvalidInterval.Code)
+}
+
+func testUnknownSymbol(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, 3, 100)})
+
+	_, err := service.NewCandleStickService().Symbol("ETHUSDT").Period(models.M1).Do(context.Background())
+	checkAPIError(t, err, mock.ErrInvalidSymbol.Code)
+}
+
+func testNoSymbol(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, 3, 100)})
+
+	_, err := service.NewCandleStickService().Period(models.M1).Do(context.Background())
+	checkAPIError(t, err, mock.ErrMandatoryParameter.Code)
+}
+
+func testIllegalSymbol(t *testing.T, seed Seeder) {
+	service := seed(t, []mock.CandleSticks{series("BTCUSDT", models.M1, 3, 100)})
+
+	for _, symbol := range []string{"btcusdt", "BTC/USDT", "BTCUSDTBTCUSDTBTCUSDT"} {
+		_, err := service.NewCandleStickService().Symbol(symbol).Period(models.M1).Do(context.Background())
+		checkAPIError(t, err, mock.ErrIllegalSymbol.Code)
+	}
+}
+
+func testValidationAfterLimit(t *testing.T, seed Seeder) {
+	s := series("BTCUSDT", models.M1, 10, 100)
+	for i := range s.CandleSticks {
+		s.CandleSticks[i].Time = s.CandleSticks[i].Time.Truncate(time.Minute)
+	}
+	// Low and high don't bound open and close
+	s.CandleSticks[2].High = s.CandleSticks[2].Low
+	s.CandleSticks[8].High = s.CandleSticks[8].Low
+	service := seed(t, []mock.CandleSticks{s})
+
+	// The invalid candlestick out of the limit is not validated
+	cs := fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).EndTime(s.CandleSticks[7].Time).Limit(3).
+		Validation(binance.ValidationPolicyReject))
+	checkCandleSticks(t, s.CandleSticks[5:8], cs)
+
+	// The invalid candlestick in the limit is dropped without replacement
+	cs = fetch(t, service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).Limit(3).
+		Validation(binance.ValidationPolicyDrop))
+	checkCandleSticks(t, []models.CandleStick{s.CandleSticks[7], s.CandleSticks[9]}, cs)
+}
@@ -0,0 +1,19 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/conformance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Suite{
+		Seed: func(t *testing.T, cs []mock.CandleSticks) binance.ServiceInterface {
+			srv, service := newTestServer(t)
+			srv.AddCandleSticks(cs)
+			return service
+		},
+	}.Run(t)
+}
@@ -0,0 +1,146 @@
+package fake
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cryptellation/binance.go/pkg/adapters"
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+	"github.com/cryptellation/models.go"
+)
+
+const (
+	// DefaultKLinesLimit is the limit of the klines endpoint when none is specified
+	DefaultKLinesLimit = binance.DefaultCandleStickLimit
+	// MaxKLinesLimit is the maximum limit of the klines endpoint
+	MaxKLinesLimit = binance.MaxCandleStickLimit
+)
+
+// kline is a candlestick with its optional volume
+type kline struct {
+	candle models.CandleStick
+	volume float64
+}
+
+// parseOptionalInt will parse the parameter if it is set
+func parseOptionalInt(params url.Values, key string) (value int64, set bool, err error) {
+	s := params.Get(key)
+	if s == "" {
+		return 0, false, nil
+	}
+
+	value, err = strconv.ParseInt(s, 10, 64)
+	return value, true, err
+}
+
+func (s *Server) handleKLines(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	// Check symbol and interval
+	symbol, interval := params.Get("symbol"), params.Get("interval")
+	if symbol == "" || interval == "" {
+		writeError(w, http.StatusBadRequest, ErrMandatoryParameter)
+		return
+	}
+	if !mock.SymbolPattern.MatchString(symbol) {
+		writeError(w, http.StatusBadRequest, ErrIllegalSymbol)
+		return
+	}
+
+	period, err := adapters.IntervalToPeriod(interval)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidInterval)
+		return
+	}
+
+	// Check limit and time bounds
+	limit, limitSet, limitErr := parseOptionalInt(params, "limit")
+	startTime, startSet, startErr := parseOptionalInt(params, "startTime")
+	endTime, endSet, endErr := parseOptionalInt(params, "endTime")
+	if limitErr != nil || startErr != nil || endErr != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+		return
+	}
+
+	if !limitSet {
+		limit = DefaultKLinesLimit
+	} else if limit <= 0 || limit > MaxKLinesLimit {
+		writeError(w, http.StatusBadRequest, ErrInvalidLimit)
+		return
+	}
+
+	kl, known := s.klines(symbol, period)
+	if !known {
+		writeError(w, http.StatusBadRequest, ErrInvalidSymbol)
+		return
+	}
+
+	// Keep klines in time bounds
+	filtered := make([]kline, 0, len(kl))
+	for _, k := range kl {
+		t := timeToBinance(k.candle.Time)
+		if startSet && t < startTime || endSet && t > endTime {
+			continue
+		}
+		filtered = append(filtered, k)
+	}
+
+	// Apply limit from start time if set, otherwise from the end
+	if int64(len(filtered)) > limit {
+		if startSet {
+			filtered = filtered[:limit]
+		} else {
+			filtered = filtered[int64(len(filtered))-limit:]
+		}
+	}
+
+	res := make([][]interface{}, len(filtered))
+	for i, k := range filtered {
+		res[i] = encodeKLine(k, period)
+	}
+
+	writeJSON(w, res)
+}
+
+// klines will return the sorted candlesticks of the symbol and period, and
+// false if the symbol is unknown
+func (s *Server) klines(symbol string, period int64) ([]kline, bool) {
+	known := false
+	kl := make([]kline, 0)
+	for _, cs := range s.mock.VolumeCandleSticks() {
+		if cs.Symbol != symbol {
+			continue
+		}
+		known = true
+
+		if cs.Period != period {
+			continue
+		}
+
+		// Series are already sorted by the mocked service
+		for i, c := range cs.CandleSticks {
+			volume, _ := cs.Volume(i)
+			kl = append(kl, kline{candle: c, volume: volume})
+		}
+	}
+
+	return kl, known
+}
+
+// encodeKLine will encode the candlestick in Binance kline format
+func encodeKLine(k kline, period int64) []interface{} {
+	bk := adapters.CandleStickToKLine(k.candle, period)
+	bk.Volume = adapters.FormatFloat(k.volume)
+	bk.QuoteAssetVolume = adapters.FormatFloat(k.volume * k.candle.Close)
+
+	// Times and trades count are numbers in Binance API
+	fields := adapters.KLineToFields(bk)
+	res := make([]interface{}, len(fields))
+	for i, f := range fields {
+		res[i] = f
+	}
+	res[0], res[6], res[8] = bk.OpenTime, bk.CloseTime, bk.TradeNum
+	return res
+}
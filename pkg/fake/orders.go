@@ -0,0 +1,318 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/binance.go/pkg/adapters"
+	"github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// commissionRateMultiplier is the multiplier that converts a commission rate
+// (e.g. 0.001) into a Binance commission expressed in basis points (e.g. 10)
+const commissionRateMultiplier = 10000
+
+// apiErrors are the Binance errors corresponding to the order validation errors
+var apiErrors = map[error]*common.APIError{
+	binance.ErrOrderNoSymbol:           ErrMandatoryParameter,
+	binance.ErrOrderNoIdentifier:       ErrMandatoryParameter,
+	binance.ErrOrderInvalidSide:        {Code: -1117, Message: "Invalid side."},
+	binance.ErrOrderInvalidType:        {Code: -1116, Message: "Invalid orderType."},
+	binance.ErrOrderInvalidTimeInForce: {Code: -1115, Message: "Invalid timeInForce."},
+	binance.ErrOrderInvalidQuantity:    {Code: -1013, Message: "Invalid quantity."},
+	binance.ErrOrderInvalidPrice:       {Code: -1013, Message: "Invalid price."},
+	binance.ErrOrderInvalidStopPrice:   {Code: -1013, Message: "Invalid stopPrice."},
+}
+
+// writeServiceError will write the error returned by the mocked service as a
+// Binance error
+func writeServiceError(w http.ResponseWriter, err error) {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		writeError(w, http.StatusBadRequest, apiErr)
+		return
+	}
+
+	for target, apiErr := range apiErrors {
+		if errors.Is(err, target) {
+			writeError(w, http.StatusBadRequest, apiErr)
+			return
+		}
+	}
+
+	writeError(w, http.StatusInternalServerError, &common.APIError{Code: -1000, Message: err.Error()})
+}
+
+// parseFloatParams will parse the float parameters, set at 0 when missing
+func parseFloatParams(params url.Values, keys ...string) ([]float64, error) {
+	values := make([]float64, len(keys))
+	for i, k := range keys {
+		s := params.Get(k)
+		if s == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+// orderRequestFromParams will create an order request from the parameters
+func orderRequestFromParams(params url.Values) (binance.OrderRequest, error) {
+	f, err := parseFloatParams(params, "quantity", "price", "stopPrice")
+	if err != nil {
+		return binance.OrderRequest{}, err
+	}
+
+	return binance.OrderRequest{
+		Symbol:        params.Get("symbol"),
+		Side:          binance.OrderSide(params.Get("side")),
+		Type:          binance.OrderType(params.Get("type")),
+		Quantity:      f[0],
+		Price:         f[1],
+		StopPrice:     f[2],
+		TimeInForce:   binance.TimeInForce(params.Get("timeInForce")),
+		ClientOrderID: params.Get("newClientOrderId"),
+	}, nil
+}
+
+// orderIdentifiersFromParams will return the order identifiers from the parameters
+func orderIdentifiersFromParams(params url.Values) (symbol string, id int64, clientOrderID string, err error) {
+	if s := params.Get("orderId"); s != "" {
+		if id, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return "", 0, "", err
+		}
+	}
+
+	return params.Get("symbol"), id, params.Get("origClientOrderId"), nil
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, params url.Values) {
+	if params.Get(methodParam) != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, ErrUnknownEndpoint)
+		return
+	}
+
+	account, err := s.mock.NewAccountService().ZeroBalances(true).Do(context.Background())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	res := gobinance.Account{
+		MakerCommission:  int64(account.MakerCommission * commissionRateMultiplier),
+		TakerCommission:  int64(account.TakerCommission * commissionRateMultiplier),
+		BuyerCommission:  int64(account.BuyerCommission * commissionRateMultiplier),
+		SellerCommission: int64(account.SellerCommission * commissionRateMultiplier),
+		CanTrade:         account.CanTrade,
+		CanWithdraw:      account.CanWithdraw,
+		CanDeposit:       account.CanDeposit,
+		Balances:         make([]gobinance.Balance, len(account.Balances)),
+	}
+	for i, b := range account.Balances {
+		res.Balances[i] = gobinance.Balance{
+			Asset:  b.Asset,
+			Free:   adapters.FormatFloat(b.Free),
+			Locked: adapters.FormatFloat(b.Locked),
+		}
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, params url.Values) {
+	switch params.Get(methodParam) {
+	case http.MethodPost:
+		s.createOrder(w, params)
+	case http.MethodGet:
+		s.getOrder(w, params)
+	case http.MethodDelete:
+		s.cancelOrder(w, params)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, ErrUnknownEndpoint)
+	}
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, params url.Values) {
+	r, err := orderRequestFromParams(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+		return
+	}
+
+	order, err := s.mock.NewCreateOrderService().
+		Symbol(r.Symbol).
+		Side(r.Side).
+		Type(r.Type).
+		Quantity(r.Quantity).
+		Price(r.Price).
+		StopPrice(r.StopPrice).
+		TimeInForce(r.TimeInForce).
+		ClientOrderID(r.ClientOrderID).
+		Do(context.Background())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, gobinance.CreateOrderResponse{
+		Symbol:                   order.Symbol,
+		OrderID:                  order.ID,
+		ClientOrderID:            order.ClientOrderID,
+		TransactTime:             timeToBinance(order.Time),
+		Price:                    adapters.FormatFloat(order.Price),
+		OrigQuantity:             adapters.FormatFloat(order.Quantity),
+		ExecutedQuantity:         adapters.FormatFloat(order.ExecutedQuantity),
+		CummulativeQuoteQuantity: adapters.FormatFloat(order.CumulativeQuoteQuantity),
+		Status:                   gobinance.OrderStatusType(order.Status),
+		TimeInForce:              gobinance.TimeInForceType(order.TimeInForce),
+		Type:                     gobinance.OrderType(order.Type),
+		Side:                     gobinance.SideType(order.Side),
+	})
+}
+
+func (s *Server) handleTestOrder(w http.ResponseWriter, params url.Values) {
+	if params.Get(methodParam) != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, ErrUnknownEndpoint)
+		return
+	}
+
+	r, err := orderRequestFromParams(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+		return
+	}
+
+	if err := r.Validate(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, struct{}{})
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, params url.Values) {
+	symbol, id, clientOrderID, err := orderIdentifiersFromParams(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+		return
+	}
+
+	order, err := s.mock.NewGetOrderService().
+		Symbol(symbol).
+		OrderID(id).
+		ClientOrderID(clientOrderID).
+		Do(context.Background())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, orderToBinance(order))
+}
+
+func (s *Server) cancelOrder(w http.ResponseWriter, params url.Values) {
+	symbol, id, clientOrderID, err := orderIdentifiersFromParams(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+		return
+	}
+
+	order, err := s.mock.NewCancelOrderService().
+		Symbol(symbol).
+		OrderID(id).
+		ClientOrderID(clientOrderID).
+		Do(context.Background())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, cancelOrderResponse(order))
+}
+
+func (s *Server) handleOpenOrders(w http.ResponseWriter, params url.Values) {
+	var orders []binance.Order
+	var err error
+
+	switch params.Get(methodParam) {
+	case http.MethodGet:
+		orders, err = s.mock.NewListOpenOrdersService().
+			Symbol(params.Get("symbol")).
+			Do(context.Background())
+	case http.MethodDelete:
+		orders, err = s.mock.NewCancelOpenOrdersService().
+			Symbol(params.Get("symbol")).
+			Do(context.Background())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, ErrUnknownEndpoint)
+		return
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	res := make([]interface{}, len(orders))
+	for i, o := range orders {
+		if params.Get(methodParam) == http.MethodDelete {
+			res[i] = cancelOrderResponse(o)
+		} else {
+			res[i] = orderToBinance(o)
+		}
+	}
+
+	writeJSON(w, res)
+}
+
+func orderToBinance(o binance.Order) gobinance.Order {
+	return gobinance.Order{
+		Symbol:                   o.Symbol,
+		OrderID:                  o.ID,
+		ClientOrderID:            o.ClientOrderID,
+		Price:                    adapters.FormatFloat(o.Price),
+		OrigQuantity:             adapters.FormatFloat(o.Quantity),
+		ExecutedQuantity:         adapters.FormatFloat(o.ExecutedQuantity),
+		CummulativeQuoteQuantity: adapters.FormatFloat(o.CumulativeQuoteQuantity),
+		Status:                   gobinance.OrderStatusType(o.Status),
+		TimeInForce:              gobinance.TimeInForceType(o.TimeInForce),
+		Type:                     gobinance.OrderType(o.Type),
+		Side:                     gobinance.SideType(o.Side),
+		StopPrice:                adapters.FormatFloat(o.StopPrice),
+		IcebergQuantity:          "0",
+		Time:                     timeToBinance(o.Time),
+		UpdateTime:               timeToBinance(o.UpdateTime),
+		IsWorking:                o.Status.IsOpen(),
+	}
+}
+
+// cancelOrderResponse will create a cancel response for the order. Its order
+// list ID is always -1, as Binance does for orders that are not part of an OCO.
+func cancelOrderResponse(o binance.Order) gobinance.CancelOrderResponse {
+	return gobinance.CancelOrderResponse{
+		Symbol:                   o.Symbol,
+		OrigClientOrderID:        o.ClientOrderID,
+		OrderID:                  o.ID,
+		OrderListID:              -1,
+		ClientOrderID:            o.ClientOrderID,
+		TransactTime:             timeToBinance(o.UpdateTime),
+		Price:                    adapters.FormatFloat(o.Price),
+		OrigQuantity:             adapters.FormatFloat(o.Quantity),
+		ExecutedQuantity:         adapters.FormatFloat(o.ExecutedQuantity),
+		CummulativeQuoteQuantity: adapters.FormatFloat(o.CumulativeQuoteQuantity),
+		Status:                   gobinance.OrderStatusType(o.Status),
+		TimeInForce:              gobinance.TimeInForceType(o.TimeInForce),
+		Type:                     gobinance.OrderType(o.Type),
+		Side:                     gobinance.SideType(o.Side),
+	}
+}
@@ -0,0 +1,286 @@
+// Package fake provides an offline stand-in for Binance REST API, that can be
+// used to exercise the real service without network access nor credentials.
+package fake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+)
+
+// UsedWeightHeader is the header where Binance sets the request weight used
+// during the current minute
+const UsedWeightHeader = "X-MBX-USED-WEIGHT-1M"
+
+var (
+	// ErrMandatoryParameter is returned when a mandatory parameter is missing
+	ErrMandatoryParameter = mock.ErrMandatoryParameter
+	// ErrIllegalSymbol is returned when the symbol has illegal characters
+	ErrIllegalSymbol = mock.ErrIllegalSymbol
+	// ErrInvalidInterval is returned when the klines interval is invalid
+	ErrInvalidInterval = mock.ErrInvalidInterval
+	// ErrInvalidSymbol is returned when the symbol is unknown
+	ErrInvalidSymbol = mock.ErrInvalidSymbol
+	// ErrInvalidLimit is returned when the limit is out of bounds
+	ErrInvalidLimit = mock.ErrInvalidLimit
+	// ErrInvalidParameter is returned when a parameter can't be parsed
+	ErrInvalidParameter = &common.APIError{Code: -1100, Message: "Illegal characters found in a parameter."}
+	// ErrInvalidAPIKey is returned when the API key is not the expected one
+	ErrInvalidAPIKey = &common.APIError{Code: -2014, Message: "API-key format invalid."}
+	// ErrInvalidSignature is returned when the request signature is not valid
+	ErrInvalidSignature = &common.APIError{Code: -1022, Message: "Signature for this request is not valid."}
+	// ErrUnknownEndpoint is returned when the endpoint is not implemented
+	ErrUnknownEndpoint = &common.APIError{Code: -1000, Message: "An unknown error occured while processing the request."}
+)
+
+// Server is a fake Binance REST API server. Candlesticks are seeded like on
+// the mocked service, while account and orders endpoints are backed by a
+// mocked service that can be accessed with Mock().
+type Server struct {
+	*httptest.Server
+
+	mock *mock.MockedService
+
+	mutex        sync.RWMutex
+	apiKey       string
+	secretKey    string
+	listenKeys   map[string]struct{}
+	weightMinute int64
+	usedWeight   int
+}
+
+// NewServer will create and start a fake Binance server, it should be closed
+// after use
+func NewServer() *Server {
+	s := &Server{
+		mock:       mock.New(),
+		listenKeys: make(map[string]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/ping", s.handlePing)
+	mux.HandleFunc("/api/v3/time", s.handleTime)
+	mux.HandleFunc("/api/v3/klines", s.handleKLines)
+	mux.HandleFunc("/api/v3/account", s.signed(s.handleAccount))
+	mux.HandleFunc("/api/v3/order", s.signed(s.handleOrder))
+	mux.HandleFunc("/api/v3/order/test", s.signed(s.handleTestOrder))
+	mux.HandleFunc("/api/v3/openOrders", s.signed(s.handleOpenOrders))
+	mux.HandleFunc("/api/v3/userDataStream", s.withAPIKey(s.handleUserDataStream))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, ErrUnknownEndpoint)
+	})
+
+	s.Server = httptest.NewServer(s.weighted(mux))
+	return s
+}
+
+// Options will return the options to create a real service using this server
+func (s *Server) Options() []binance.Option {
+	return []binance.Option{
+		binance.WithBaseURL(s.URL),
+	}
+}
+
+// Mock will return the mocked service backing the account and orders
+// endpoints, that can be used to set balances or check orders
+func (s *Server) Mock() *mock.MockedService {
+	return s.mock
+}
+
+// AddCandleSticks will add candlesticks that will be served by the klines endpoint
+func (s *Server) AddCandleSticks(cs []mock.CandleSticks) {
+	s.mock.AddCandleSticks(cs)
+}
+
+// AddVolumeCandleSticks will add candlesticks with their volumes that will be
+// served by the klines endpoint
+func (s *Server) AddVolumeCandleSticks(cs []mock.VolumeCandleSticks) {
+	s.mock.AddVolumeCandleSticks(cs)
+}
+
+// SetCredentials will set the API key and secret key expected on signed
+// requests. If no credentials are set, signed requests are not checked.
+func (s *Server) SetCredentials(apiKey, secretKey string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.apiKey = apiKey
+	s.secretKey = secretKey
+}
+
+// weighted will set the used weight header on every response
+func (s *Server) weighted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		minute := time.Now().Unix() / 60
+		if minute != s.weightMinute {
+			s.weightMinute, s.usedWeight = minute, 0
+		}
+		s.usedWeight++
+		w.Header().Set(UsedWeightHeader, strconv.Itoa(s.usedWeight))
+		s.mutex.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withAPIKey will check the API key of the request before calling the handler
+func (s *Server) withAPIKey(handler func(w http.ResponseWriter, params url.Values)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, _, err := requestParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+			return
+		}
+
+		s.mutex.RLock()
+		apiKey := s.apiKey
+		s.mutex.RUnlock()
+
+		if apiKey != "" && r.Header.Get("X-MBX-APIKEY") != apiKey {
+			writeError(w, http.StatusUnauthorized, ErrInvalidAPIKey)
+			return
+		}
+
+		handler(w, mergeMethod(params, r.Method))
+	}
+}
+
+// signed will check the API key and the signature of the request before
+// calling the handler
+func (s *Server) signed(handler func(w http.ResponseWriter, params url.Values)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, payload, err := requestParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidParameter)
+			return
+		}
+
+		if params.Get("timestamp") == "" || params.Get("signature") == "" {
+			writeError(w, http.StatusBadRequest, ErrMandatoryParameter)
+			return
+		}
+
+		s.mutex.RLock()
+		apiKey, secretKey := s.apiKey, s.secretKey
+		s.mutex.RUnlock()
+
+		if apiKey != "" {
+			if r.Header.Get("X-MBX-APIKEY") != apiKey {
+				writeError(w, http.StatusUnauthorized, ErrInvalidAPIKey)
+				return
+			}
+
+			mac := hmac.New(sha256.New, []byte(secretKey))
+			_, _ = mac.Write([]byte(payload))
+			if hex.EncodeToString(mac.Sum(nil)) != params.Get("signature") {
+				writeError(w, http.StatusBadRequest, ErrInvalidSignature)
+				return
+			}
+		}
+
+		handler(w, mergeMethod(params, r.Method))
+	}
+}
+
+// mergeMethod will add the HTTP method to the parameters, under a key that
+// can't be a Binance parameter
+func mergeMethod(params url.Values, method string) url.Values {
+	params.Set(methodParam, method)
+	return params
+}
+
+// methodParam is the parameter key where the HTTP method is stored
+const methodParam = ":method"
+
+// requestParams will return the parameters from the query and the body of the
+// request, and the payload that has been signed
+func requestParams(r *http.Request) (url.Values, string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return nil, "", err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, "", err
+	}
+	for k, v := range form {
+		params[k] = v
+	}
+
+	// The signature is appended at the end of the query string
+	query := r.URL.RawQuery
+	if i := strings.Index(query, "signature="); i >= 0 {
+		query = strings.TrimSuffix(query[:i], "&")
+	}
+
+	return params, query + string(body), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err *common.APIError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, struct{}{})
+}
+
+func (s *Server) handleTime(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]int64{
+		"serverTime": timeToBinance(time.Now()),
+	})
+}
+
+func (s *Server) handleUserDataStream(w http.ResponseWriter, params url.Values) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch params.Get(methodParam) {
+	case http.MethodPost:
+		listenKey := strconv.FormatInt(time.Now().UnixNano(), 36)
+		s.listenKeys[listenKey] = struct{}{}
+		writeJSON(w, map[string]string{"listenKey": listenKey})
+	case http.MethodPut, http.MethodDelete:
+		listenKey := params.Get("listenKey")
+		if _, ok := s.listenKeys[listenKey]; !ok {
+			writeError(w, http.StatusBadRequest, &common.APIError{Code: -1125, Message: "This listenKey does not exist."})
+			return
+		}
+		if params.Get(methodParam) == http.MethodDelete {
+			delete(s.listenKeys, listenKey)
+		}
+		writeJSON(w, struct{}{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, ErrUnknownEndpoint)
+	}
+}
+
+func timeToBinance(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,225 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+	"github.com/cryptellation/models.go"
+)
+
+func newTestServer(t *testing.T) (*Server, binance.ServiceInterface) {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.SetCredentials("api-key", "secret-key")
+	return srv, binance.New("api-key", "secret-key", srv.Options()...)
+}
+
+func testCandleSticks(count int) []mock.VolumeCandleSticks {
+	cs := mock.VolumeCandleSticks{Symbol: "BTCUSDT", Period: models.M1}
+	for i := 0; i < count; i++ {
+		f := float64(i)
+		cs.CandleSticks = append(cs.CandleSticks, models.CandleStick{
+			Time: time.Unix(int64(60*i), 0), Open: f, High: f + 2, Low: f - 1, Close: f + 1,
+		})
+		cs.Volumes = append(cs.Volumes, 10*f)
+	}
+	return []mock.VolumeCandleSticks{cs}
+}
+
+func checkAPIError(t *testing.T, err error, code int64) {
+	var apiErr *common.APIError
+	if !errors.As(err, &apiErr) {
+		t.Error("There should be an API error, but there is", err)
+	} else if apiErr.Code != code {
+		t.Error("Error code should be", code, "but is", apiErr.Code)
+	}
+}
+
+func TestServerCandleSticks(t *testing.T) {
+	srv, service := newTestServer(t)
+	srv.AddVolumeCandleSticks(testCandleSticks(10))
+
+	cs, err := service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).Limit(3).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if len(cs) != 3 {
+		t.Fatal("There should be 3 candlesticks but there is", len(cs))
+	}
+	if !cs[0].Time.Equal(time.Unix(7*60, 0)) || cs[2].Close != 10 {
+		t.Error("Latest candlesticks should be returned but there is", cs)
+	}
+
+	// With an end time
+	cs, err = service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).Limit(2).EndTime(time.Unix(4*60, 0)).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if len(cs) != 2 || !cs[1].Time.Equal(time.Unix(4*60, 0)) {
+		t.Error("Candlesticks should end on end time but there is", cs)
+	}
+}
+
+func TestServerCandleSticksErrors(t *testing.T) {
+	srv, service := newTestServer(t)
+	srv.AddVolumeCandleSticks(testCandleSticks(1))
+
+	_, err := service.NewCandleStickService().
+		Symbol("ETHUSDT").Period(models.M1).
+		Do(context.Background())
+	checkAPIError(t, err, ErrInvalidSymbol.Code)
+
+	_, err = service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(42).
+		Do(context.Background())
+	checkAPIError(t, err, ErrInvalidInterval.Code)
+
+	_, err = service.NewCandleStickService().
+		Symbol("BTCUSDT").Period(models.M1).Limit(MaxKLinesLimit + 1).
+		Do(context.Background())
+	checkAPIError(t, err, ErrInvalidLimit.Code)
+}
+
+func TestServerUsedWeight(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	for i := 1; i <= 2; i++ {
+		res, err := http.Get(srv.URL + "/api/v3/ping")
+		if err != nil {
+			t.Fatal("There should be no error but there is", err)
+		}
+		res.Body.Close()
+
+		if w := res.Header.Get(UsedWeightHeader); w != "1" && i == 1 || w != "2" && i == 2 {
+			t.Error("Used weight should be", i, "but is", w)
+		}
+	}
+}
+
+func TestServerAccount(t *testing.T) {
+	srv, service := newTestServer(t)
+	srv.Mock().SetBalance("BTC", 1.5, 0.5)
+	srv.Mock().SetCommissions(0.001, 0.002)
+
+	account, err := service.NewAccountService().Do(context.Background())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if account.MakerCommission != 0.001 || account.TakerCommission != 0.002 {
+		t.Error("Commissions are not correct:", account)
+	}
+
+	b, ok := account.Balance("BTC")
+	if !ok || b.Free != 1.5 || b.Locked != 0.5 {
+		t.Error("Balance is not correct:", b)
+	}
+}
+
+func TestServerCredentials(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	_, err := binance.New("api-key", "wrong-secret", srv.Options()...).
+		NewAccountService().Do(context.Background())
+	checkAPIError(t, err, ErrInvalidSignature.Code)
+
+	_, err = binance.New("wrong-key", "secret-key", srv.Options()...).
+		NewAccountService().Do(context.Background())
+	checkAPIError(t, err, ErrInvalidAPIKey.Code)
+}
+
+func TestServerOrders(t *testing.T) {
+	_, service := newTestServer(t)
+	ctx := context.Background()
+
+	created, err := service.NewCreateOrderService().
+		Symbol("BTCUSDT").Side(binance.OrderSideBuy).Type(binance.OrderTypeLimit).
+		Quantity(1).Price(100).ClientOrderID("my-order").
+		Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if created.ID == 0 || created.ClientOrderID != "my-order" || created.Status != binance.OrderStatusNew {
+		t.Error("Created order is not correct:", created)
+	}
+
+	got, err := service.NewGetOrderService().Symbol("BTCUSDT").ClientOrderID("my-order").Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if got.ID != created.ID || got.Price != 100 || got.Quantity != 1 {
+		t.Error("Got order is not correct:", got)
+	}
+
+	open, err := service.NewListOpenOrdersService().Symbol("BTCUSDT").Do(ctx)
+	if err != nil || len(open) != 1 {
+		t.Error("There should be 1 open order but there is", open, err)
+	}
+
+	canceled, err := service.NewCancelOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if canceled.Status != binance.OrderStatusCanceled {
+		t.Error("Order should be canceled but is", canceled.Status)
+	}
+
+	_, err = service.NewCancelOpenOrdersService().Symbol("BTCUSDT").Do(ctx)
+	checkAPIError(t, err, mock.ErrUnknownOrder.Code)
+
+	_, err = service.NewGetOrderService().Symbol("BTCUSDT").OrderID(42).Do(ctx)
+	checkAPIError(t, err, mock.ErrOrderDoesNotExist.Code)
+}
+
+func TestServerCancelOpenOrders(t *testing.T) {
+	_, service := newTestServer(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := service.NewCreateOrderService().
+			Symbol("BTCUSDT").Side(binance.OrderSideSell).Type(binance.OrderTypeLimit).
+			Quantity(1).Price(100).
+			Do(ctx)
+		if err != nil {
+			t.Fatal("There should be no error but there is", err)
+		}
+	}
+
+	canceled, err := service.NewCancelOpenOrdersService().Symbol("BTCUSDT").Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if len(canceled) != 2 {
+		t.Error("There should be 2 canceled orders but there is", len(canceled))
+	}
+}
+
+func TestServerOrderValidation(t *testing.T) {
+	srv, _ := newTestServer(t)
+	service := binance.NewWithTestOrders("api-key", "secret-key", srv.Options()...)
+
+	_, err := service.NewCreateOrderService().
+		Symbol("BTCUSDT").Side(binance.OrderSideBuy).Type(binance.OrderTypeMarket).
+		Quantity(1).
+		Do(context.Background())
+	if err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+
+	if len(srv.Mock().Orders()) != 0 {
+		t.Error("Test orders should not be created")
+	}
+}
@@ -0,0 +1,142 @@
+package mock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// account is the account state shared between the mocked service and its
+// account services
+type account struct {
+	mutex    sync.RWMutex
+	info     interfaces.Account
+	balances map[string]interfaces.Balance
+}
+
+func newAccount() *account {
+	return &account{
+		info: interfaces.Account{
+			CanTrade:    true,
+			CanWithdraw: true,
+			CanDeposit:  true,
+		},
+		balances: make(map[string]interfaces.Balance),
+	}
+}
+
+// update will add the free and locked deltas to the asset balance
+func (a *account) update(asset string, freeDelta, lockedDelta float64) interfaces.Balance {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	b := a.balances[asset]
+	b.Asset = asset
+	b.Free += freeDelta
+	b.Locked += lockedDelta
+	a.balances[asset] = b
+	return b
+}
+
+// balance will return the asset balance
+func (a *account) balance(asset string) interfaces.Balance {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	b, ok := a.balances[asset]
+	if !ok {
+		return interfaces.Balance{Asset: asset}
+	}
+	return b
+}
+
+// commissions will return the maker and taker commission rates
+func (a *account) commissions() (maker, taker float64) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.info.MakerCommission, a.info.TakerCommission
+}
+
+// snapshot will return a copy of the account with sorted balances
+func (a *account) snapshot(zeroBalances bool) interfaces.Account {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	info := a.info
+	info.Balances = make([]interfaces.Balance, 0, len(a.balances))
+	for _, b := range a.balances {
+		if !zeroBalances && b.IsZero() {
+			continue
+		}
+		info.Balances = append(info.Balances, b)
+	}
+
+	sort.Slice(info.Balances, func(i, j int) bool {
+		return info.Balances[i].Asset < info.Balances[j].Asset
+	})
+
+	return info
+}
+
+// AccountService is the mocked service for account
+type AccountService struct {
+	account *account
+
+	zeroBalances bool
+	err          error
+	injector     *injector
+	calls        *calls
+}
+
+func newAccountService(a *account) *AccountService {
+	return &AccountService{
+		account: a,
+	}
+}
+
+// Do will execute a request for account informations
+func (m *AccountService) Do(ctx context.Context) (interfaces.Account, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{Operation: OpAccount}, start, len(res.Balances), err)
+	return res, err
+}
+
+// DoExact will execute a request for account informations as Do, with the
+// exact decimal amounts of the balances
+func (m *AccountService) DoExact(ctx context.Context) (interfaces.ExactAccount, error) {
+	a, err := m.Do(ctx)
+	if err != nil {
+		return interfaces.ExactAccount{}, err
+	}
+	return interfaces.NewExactAccount(a), nil
+}
+
+func (m *AccountService) do(ctx context.Context) (interfaces.Account, error) {
+	if m.err != nil {
+		return interfaces.Account{}, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpAccount, ""); err != nil {
+		return interfaces.Account{}, err
+	}
+
+	return m.account.snapshot(m.zeroBalances), nil
+}
+
+// ZeroBalances will specify if the balances with nothing free nor locked
+// should be kept in the next account request
+func (m *AccountService) ZeroBalances(keep bool) interfaces.AccountServiceInterface {
+	m.zeroBalances = keep
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *AccountService) SetError(err error) {
+	m.err = err
+}
@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMockedAccountDo(t *testing.T) {
+	m := New()
+	m.SetBalance("USDC", 100, 50)
+	m.SetBalance("BTC", 1, 0)
+	m.SetBalance("ETH", 0, 0)
+
+	a, err := m.NewAccountService().Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if len(a.Balances) != 2 {
+		t.Fatal("There should be 2 balances, but there is", len(a.Balances))
+	}
+
+	if a.Balances[0].Asset != "BTC" || a.Balances[1].Asset != "USDC" {
+		t.Error("Balances should be sorted by asset:", a.Balances)
+	}
+
+	if b, ok := a.Balance("USDC"); !ok || b.Free != 100 || b.Locked != 50 {
+		t.Error("USDC balance is not correct:", b)
+	}
+}
+
+func TestMockedAccountDo_ZeroBalances(t *testing.T) {
+	m := New()
+	m.SetBalance("USDC", 100, 0)
+	m.SetBalance("ETH", 0, 0)
+
+	a, _ := m.NewAccountService().ZeroBalances(true).Do(context.TODO())
+	if len(a.Balances) != 2 {
+		t.Fatal("There should be 2 balances, but there is", len(a.Balances))
+	}
+}
+
+func TestMockedAccountDo_UpdateBalance(t *testing.T) {
+	m := New()
+	s := m.NewAccountService()
+
+	m.UpdateBalance("USDC", 100, 0)
+	m.UpdateBalance("USDC", -30, 30)
+
+	a, _ := s.Do(context.TODO())
+	if b, ok := a.Balance("USDC"); !ok || b.Free != 70 || b.Locked != 30 {
+		t.Error("USDC balance is not correct:", b)
+	}
+
+	if b := m.Balance("USDC"); b.Free != 70 || b.Locked != 30 {
+		t.Error("USDC balance is not correct:", b)
+	}
+}
+
+func TestMockedAccountDo_CommissionsAndPermissions(t *testing.T) {
+	m := New()
+	m.SetCommissions(0.001, 0.002)
+	m.SetPermissions(false, true, true)
+
+	a, _ := m.NewAccountService().Do(context.TODO())
+	if a.MakerCommission != 0.001 || a.TakerCommission != 0.002 {
+		t.Error("Commissions are not correct:", a.MakerCommission, a.TakerCommission)
+	}
+
+	if a.CanTrade || !a.CanWithdraw || !a.CanDeposit {
+		t.Error("Permissions are not correct:", a.CanTrade, a.CanWithdraw, a.CanDeposit)
+	}
+}
+
+func TestMockedAccountDo_Error(t *testing.T) {
+	m := New()
+	m.NextError(errors.New("Some error"))
+	if _, err := m.NewAccountService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on account service")
+	}
+}
@@ -0,0 +1,95 @@
+package mock
+
+import (
+	"sync"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// Call is a request executed on the mocked service, with its parameters.
+// Parameters that don't apply to the operation are left empty.
+type Call struct {
+	Operation Operation
+
+	// Request parameters
+	Symbol        string
+	Period        int64
+	StartTime     time.Time
+	EndTime       time.Time
+	Limit         int
+	OrderID       int64
+	ClientOrderID string
+	OrderRequest  interfaces.OrderRequest
+
+	// Execution
+	Time     time.Time
+	Duration time.Duration
+	Count    int
+	Err      error
+}
+
+// calls is the concurrency-safe log of the calls executed on the mocked service
+type calls struct {
+	mutex sync.RWMutex
+	list  []Call
+}
+
+func newCalls() *calls {
+	return &calls{}
+}
+
+// record will add the call to the log, with its execution time from start,
+// the number of items returned and its error. A nil log records nothing.
+func (c *calls) record(call Call, start time.Time, count int, err error) {
+	if c == nil {
+		return
+	}
+
+	call.Time = start
+	call.Duration = time.Since(start)
+	call.Count = count
+	call.Err = err
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.list = append(c.list, call)
+}
+
+// Calls will return every call executed on the service, in execution order
+func (m *MockedService) Calls() []Call {
+	return m.FindCalls(func(Call) bool { return true })
+}
+
+// CallsOf will return the calls of the operation, in execution order
+func (m *MockedService) CallsOf(op Operation) []Call {
+	return m.FindCalls(func(c Call) bool { return c.Operation == op })
+}
+
+// FindCalls will return the calls matching the function, in execution order
+func (m *MockedService) FindCalls(match func(Call) bool) []Call {
+	m.calls.mutex.RLock()
+	defer m.calls.mutex.RUnlock()
+
+	list := make([]Call, 0)
+	for _, c := range m.calls.list {
+		if match(c) {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// CountCalls will return the number of calls matching the function
+func (m *MockedService) CountCalls(match func(Call) bool) int {
+	return len(m.FindCalls(match))
+}
+
+// ResetCalls will remove every recorded call
+func (m *MockedService) ResetCalls() {
+	m.calls.mutex.Lock()
+	defer m.calls.mutex.Unlock()
+
+	m.calls.list = nil
+}
@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/models.go"
+)
+
+func TestCalls(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	_, _ = m.NewCandleStickService().Symbol("BTC-USDC").Period(models.M5).Limit(100).Do(context.TODO())
+	_, _ = m.NewCandleStickService().Symbol("ETH-USDC").Period(models.M5).Do(context.TODO())
+	_, _ = m.NewCreateOrderService().
+		Symbol("BTC-USDC").Side(interfaces.OrderSideBuy).Type(interfaces.OrderTypeMarket).Quantity(1).
+		Do(context.TODO())
+	_, _ = m.NewGetOrderService().Symbol("BTC-USDC").OrderID(42).Do(context.TODO())
+
+	calls := m.Calls()
+	if len(calls) != 4 {
+		t.Fatal("There should be 4 calls but there is", len(calls))
+	}
+
+	c := calls[0]
+	if c.Operation != OpCandleSticks || c.Symbol != "BTC-USDC" || c.Period != models.M5 || c.Limit != 100 {
+		t.Error("Candlesticks call parameters are not correct:", c)
+	}
+	if c.Count != 2 || c.Err != nil || c.Time.IsZero() {
+		t.Error("Candlesticks call execution is not correct:", c)
+	}
+
+	if c := calls[1]; c.Limit != DefaultCandleStickServiceLimit {
+		t.Error("Candlesticks call without limit should record the default limit but recorded", c.Limit)
+	}
+
+	if c := calls[2]; c.OrderRequest.Quantity != 1 || c.Count != 1 {
+		t.Error("Order creation call is not correct:", c)
+	}
+
+	if c := calls[3]; c.OrderID != 42 || c.Count != 0 || c.Err != ErrOrderDoesNotExist {
+		t.Error("Order query call is not correct:", c)
+	}
+
+	count := m.CountCalls(func(c Call) bool {
+		return c.Operation == OpCandleSticks && c.Symbol == "BTC-USDC" && c.Period == models.M5 && c.Limit == 100
+	})
+	if count != 1 {
+		t.Error("There should be 1 matching call but there is", count)
+	}
+
+	if n := len(m.CallsOf(OpCandleSticks)); n != 2 {
+		t.Error("There should be 2 candlesticks calls but there is", n)
+	}
+
+	m.ResetCalls()
+	if n := len(m.Calls()); n != 0 {
+		t.Error("There should be no call after reset but there is", n)
+	}
+}
+
+func TestCalls_Concurrent(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = m.NewCandleStickService().Do(context.TODO())
+			_ = m.Calls()
+		}()
+	}
+	wg.Wait()
+
+	if n := len(m.Calls()); n != 10 {
+		t.Error("There should be 10 calls but there is", n)
+	}
+}
@@ -2,14 +2,39 @@ package mock
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"sort"
 	"time"
 
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/cryptellation/binance.go/pkg/adapters"
 	interfaces "github.com/cryptellation/binance.go/pkg/binance"
 	"github.com/cryptellation/models.go"
 )
 
+var (
+	// ErrMandatoryParameter is the error returned by Binance when a mandatory
+	// parameter is missing. It is not returned by the mocked candlestick
+	// service without symbol, as it then returns the candlesticks of every
+	// symbol.
+	ErrMandatoryParameter = &common.APIError{Code: -1102, Message: "Mandatory parameter was not sent, was empty/null, or malformed."}
+	// ErrIllegalSymbol is the error returned by Binance when the symbol has
+	// characters out of SymbolPattern
+	ErrIllegalSymbol = &common.APIError{Code: -1100, Message: "Illegal characters found in parameter 'symbol'; legal range is '^[A-Z0-9-_.]{1,20}$'."}
+	// ErrInvalidInterval is the error returned by Binance when the candlesticks period is not supported
+	ErrInvalidInterval = &common.APIError{Code: -1120, Message: "Invalid interval."}
+	// ErrInvalidSymbol is the error returned by Binance when the symbol does not exist
+	ErrInvalidSymbol = &common.APIError{Code: -1121, Message: "Invalid symbol."}
+	// ErrInvalidLimit is the error returned by Binance when the limit is out of bounds
+	ErrInvalidLimit = &common.APIError{Code: -1130, Message: "Data sent for parameter 'limit' is not valid."}
+)
+
+// SymbolPattern is the pattern of the symbols accepted by Binance
+var SymbolPattern = regexp.MustCompile(`^[A-Z0-9-_.]{1,20}$`)
+
 // DefaultCandleStickServiceLimit is the limit for CandleStick service if none is specified
-var DefaultCandleStickServiceLimit = 1000
+var DefaultCandleStickServiceLimit = interfaces.DefaultCandleStickLimit
 
 // TestCandleSticks are candle sticks that can be used for test
 var TestCandleSticks = []CandleSticks{
@@ -25,13 +50,13 @@ var TestCandleSticks = []CandleSticks{
 	},
 	{
 		Symbol: "IOTA-USDC", Period: models.M15, CandleSticks: []models.CandleStick{
-			{Time: time.Unix(1257894000, 0), Open: 30, High: 30, Low: 30, Close: 30},
-			{Time: time.Unix(1257894900, 0), Open: 35, High: 35, Low: 35, Close: 35}},
+			{Time: time.Unix(1257894000, 0).UTC(), Open: 30, High: 30, Low: 30, Close: 30},
+			{Time: time.Unix(1257894900, 0).UTC(), Open: 35, High: 35, Low: 35, Close: 35}},
 	},
 	{
 		Symbol: "BTC-USDC", Period: models.M5, CandleSticks: []models.CandleStick{
-			{Time: time.Unix(1257894000, 0), Open: 30, High: 30, Low: 30, Close: 30},
-			{Time: time.Unix(1257894300, 0), Open: 35, High: 35, Low: 35, Close: 35}},
+			{Time: time.Unix(1257894000, 0).UTC(), Open: 30, High: 30, Low: 30, Close: 30},
+			{Time: time.Unix(1257894300, 0).UTC(), Open: 35, High: 35, Low: 35, Close: 35}},
 	},
 }
 
@@ -50,92 +75,273 @@ type CandleSticks struct {
 	CandleSticks []models.CandleStick
 }
 
-// CandleStickService is the mocked service for candlesticks
+// VolumeCandleSticks are CandleSticks with their optional base asset
+// volumes, in the same order. Volumes are used by the paper exchange to limit
+// the fills.
+type VolumeCandleSticks struct {
+	Symbol       string
+	Period       int64
+	CandleSticks []models.CandleStick
+	Volumes      []float64
+
+	// noVolume tells which candlesticks have no volume when series with and
+	// without volumes are combined by the mocked service
+	noVolume []bool
+}
+
+// Volume will return the volume of the i-th candlestick and true, or false
+// if there is no volume for this candlestick
+func (cs VolumeCandleSticks) Volume(i int) (float64, bool) {
+	if i >= len(cs.Volumes) || (i < len(cs.noVolume) && cs.noVolume[i]) {
+		return 0, false
+	}
+	return cs.Volumes[i], true
+}
+
+// WithoutVolumes will return the candlesticks without their volumes
+func (cs VolumeCandleSticks) WithoutVolumes() CandleSticks {
+	return CandleSticks{
+		Symbol:       cs.Symbol,
+		Period:       cs.Period,
+		CandleSticks: cs.CandleSticks,
+	}
+}
+
+// CandleStickService is the mocked service for candlesticks, it is a builder
+// for a CandleStickRequest
 type CandleStickService struct {
-	candleSticks []CandleSticks
+	store *candleStore
 
-	symbol  string
-	period  int64
-	endTime time.Time
-	limit   int
-	err     error
+	request  interfaces.CandleStickRequest
+	err      error
+	injector *injector
+	calls    *calls
 }
 
 func newCandleStickService(cs []CandleSticks) *CandleStickService {
 	return &CandleStickService{
-		candleSticks: cs,
-		limit:        DefaultCandleStickServiceLimit,
+		store: newCandleStore(cs...),
 	}
 }
 
-// Do will execute a request for candlesticks
+// Do will execute a request for candlesticks. As on Binance, time bounds are
+// inclusive on candlesticks open time and, when more than the limit match,
+// the earliest candlesticks are returned for a start-bounded request and the
+// latest ones otherwise.
 func (m *CandleStickService) Do(ctx context.Context) ([]models.CandleStick, error) {
-	cs := make([]models.CandleStick, 0)
-	count := 0
+	ecs, err := m.DoExact(ctx)
+
+	cs := make([]models.CandleStick, len(ecs))
+	for i, c := range ecs {
+		cs[i] = c.CandleStick
+	}
+	return cs, err
+}
+
+// DoExact will execute a request for candlesticks as Do, with their exact
+// decimal values and close times. The volume is empty if the candlestick has
+// no volume.
+func (m *CandleStickService) DoExact(ctx context.Context) ([]interfaces.ExactCandleStick, error) {
+	r := m.request
+
+	start := time.Now()
+	cs, err := m.do(ctx, r)
+	m.calls.record(Call{
+		Operation: OpCandleSticks,
+		Symbol:    r.Symbol,
+		Period:    r.Period,
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+		Limit:     effectiveLimit(r),
+	}, start, len(cs), err)
+	return cs, err
+}
+
+func (m *CandleStickService) do(ctx context.Context, r interfaces.CandleStickRequest) ([]interfaces.ExactCandleStick, error) {
+	cs := make([]interfaces.ExactCandleStick, 0)
 
 	if m.err != nil {
 		return cs, m.err
 	}
 
-	for _, t := range m.candleSticks {
+	if err := m.injector.inject(ctx, OpCandleSticks, r.Symbol); err != nil {
+		return cs, err
+	}
+
+	series := m.store.snapshot()
+	if err := checkCandleStickRequest(r, series); err != nil {
+		return cs, err
+	}
+
+	// Gather the candlesticks of the requested sets in time bounds
+	candles := make([]setCandleStick, 0)
+	for si, t := range series {
 		// Check if symbol is set and correspond
-		if m.symbol != "" && t.Symbol != m.symbol {
+		if r.Symbol != "" && t.Symbol != r.Symbol {
 			continue
 		}
 
 		// Check if period is set and correspond
-		// TODO check if period is valid or throw an error
-		if m.period != 0 && t.Period != m.period {
+		if r.Period != 0 && t.Period != r.Period {
 			continue
 		}
 
-		// Check each candle
-		for _, c := range t.CandleSticks {
-			// Check if count as trespassed limit
-			if count >= m.limit {
-				break
+		// Check each candle is in time bounds
+		for i, c := range t.CandleSticks {
+			if !r.StartTime.IsZero() && c.Time.Before(r.StartTime) {
+				continue
 			}
 
-			// Check if endtime is send and correspond
-			if !m.endTime.IsZero() && m.endTime.After(c.Time) {
+			if !r.EndTime.IsZero() && c.Time.After(r.EndTime) {
 				continue
 			}
 
-			// Add it if it passed tests
-			cs = append(cs, c)
-			count++
+			candles = append(candles, setCandleStick{set: si, candle: exactCandleStick(t, i)})
+		}
+	}
+
+	// Sort chronologically across candlesticks sets
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].candle.Time.Before(candles[j].candle.Time)
+	})
+
+	// Apply limit from the start if start-bounded, from the end otherwise
+	limit := effectiveLimit(r)
+	if len(candles) > limit {
+		if !r.StartTime.IsZero() {
+			candles = candles[:limit]
+		} else {
+			candles = candles[len(candles)-limit:]
+		}
+	}
+
+	// Validate the returned candlesticks of each set, as the real service
+	// validates the ones returned by Binance, and gather violations if rejected
+	sets := make([][]interfaces.ExactCandleStick, len(series))
+	for _, c := range candles {
+		sets[c.set] = append(sets[c.set], c.candle)
+	}
+
+	violations := make([]interfaces.Violation, 0)
+	for si, set := range sets {
+		if len(set) == 0 {
+			continue
 		}
+
+		set, err := r.Validation.Apply(series[si].Symbol, series[si].Period, set)
+		var verr *interfaces.ValidationError
+		if errors.As(err, &verr) {
+			violations = append(violations, verr.Violations...)
+		} else if err != nil {
+			return cs, err
+		}
+		cs = append(cs, set...)
+	}
+
+	if len(violations) > 0 {
+		return make([]interfaces.ExactCandleStick, 0), &interfaces.ValidationError{Violations: violations}
 	}
+
+	// Sort chronologically the validated candlesticks sets
+	sort.SliceStable(cs, func(i, j int) bool {
+		return cs[i].Time.Before(cs[j].Time)
+	})
+
 	return cs, nil
 }
 
+// setCandleStick is a candlestick with the index of its set
+type setCandleStick struct {
+	set    int
+	candle interfaces.ExactCandleStick
+}
+
+// checkCandleStickRequest will return the error Binance would return for the
+// request parameters
+// effectiveLimit will return the limit applied to the request
+func effectiveLimit(r interfaces.CandleStickRequest) int {
+	if r.Limit == 0 {
+		return DefaultCandleStickServiceLimit
+	}
+	return r.Limit
+}
+
+func checkCandleStickRequest(r interfaces.CandleStickRequest, series []VolumeCandleSticks) error {
+	if r.Symbol != "" && !SymbolPattern.MatchString(r.Symbol) {
+		return ErrIllegalSymbol
+	}
+
+	if r.Period != 0 {
+		if _, err := adapters.PeriodToInterval(r.Period); err != nil {
+			return ErrInvalidInterval
+		}
+	}
+
+	if r.Limit < 0 || r.Limit > interfaces.MaxCandleStickLimit {
+		return ErrInvalidLimit
+	}
+
+	if r.Symbol != "" {
+		for _, t := range series {
+			if t.Symbol == r.Symbol {
+				return nil
+			}
+		}
+		return ErrInvalidSymbol
+	}
+
+	return nil
+}
+
+// Request will return the request that the service will execute
+func (m *CandleStickService) Request() interfaces.CandleStickRequest {
+	return m.request
+}
+
+// WithRequest will replace the request that the service will execute
+func (m *CandleStickService) WithRequest(r interfaces.CandleStickRequest) interfaces.CandleStickServiceInterface {
+	m.request = r
+	return m
+}
+
 // Symbol will specify a symbol for next candlesticks request
 func (m *CandleStickService) Symbol(symbol string) interfaces.CandleStickServiceInterface {
-	m.symbol = symbol
+	m.request = m.request.WithSymbol(symbol)
 	return m
 }
 
 // Period will specify a period for next candlesticks request
 func (m *CandleStickService) Period(period int64) interfaces.CandleStickServiceInterface {
-	m.period = period
+	m.request = m.request.WithPeriod(period)
+	return m
+}
+
+// StartTime will specify the time where the list starts (earliest time) for
+// next candlesticks request
+func (m *CandleStickService) StartTime(startTime time.Time) interfaces.CandleStickServiceInterface {
+	m.request = m.request.WithStartTime(startTime)
 	return m
 }
 
-// EndTime will specify the time where the list ends (earliest time) for
+// EndTime will specify the time where the list ends (latest time) for
 // next candlesticks request
 func (m *CandleStickService) EndTime(endTime time.Time) interfaces.CandleStickServiceInterface {
-	m.endTime = endTime
+	m.request = m.request.WithEndTime(endTime)
 	return m
 }
 
 // Limit will specify the number of candlesticks the list should have at its maximum
-// If the limit is higher than the default limit, it will be limited to this one
+// If none is specified, DefaultCandleStickServiceLimit is used
 func (m *CandleStickService) Limit(limit int) interfaces.CandleStickServiceInterface {
-	if limit < DefaultCandleStickServiceLimit {
-		m.limit = limit
-	} else {
-		m.limit = DefaultCandleStickServiceLimit
-	}
+	m.request = m.request.WithLimit(limit)
+	return m
+}
+
+// Validation will specify the policy applied to invalid candlesticks for next
+// candlesticks request. Each candlesticks set is validated after the limit is
+// applied, as the real service validates the candlesticks returned by Binance.
+func (m *CandleStickService) Validation(policy interfaces.ValidationPolicy) interfaces.CandleStickServiceInterface {
+	m.request = m.request.WithValidation(policy)
 	return m
 }
 
@@ -144,3 +350,25 @@ func (m *CandleStickService) Limit(limit int) interfaces.CandleStickServiceInter
 func (m *CandleStickService) SetError(err error) {
 	m.err = err
 }
+
+// exactCandleStick will return the i-th candlestick of the series with the
+// decimal values of its floats and its close time, as computed by Binance
+func exactCandleStick(cs VolumeCandleSticks, i int) interfaces.ExactCandleStick {
+	c := cs.CandleSticks[i]
+	ec := interfaces.ExactCandleStick{
+		CandleStick: c,
+		CloseTime:   c.Time.Add(time.Duration(cs.Period)*time.Second - time.Millisecond),
+		Exact: interfaces.CandleStickDecimals{
+			Open:  interfaces.DecimalFromFloat(c.Open),
+			High:  interfaces.DecimalFromFloat(c.High),
+			Low:   interfaces.DecimalFromFloat(c.Low),
+			Close: interfaces.DecimalFromFloat(c.Close),
+		},
+	}
+
+	if volume, ok := cs.Volume(i); ok {
+		ec.Exact.Volume = interfaces.DecimalFromFloat(volume)
+	}
+
+	return ec
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
 	"github.com/cryptellation/models.go"
 )
 
@@ -86,18 +87,62 @@ func TestMockedIntervalDo(t *testing.T) {
 func TestMockedEndTimeDo(t *testing.T) {
 	s := newCandleStickService(TestCandleSticks)
 
-	cs, _ := s.EndTime(time.Unix(1257893900, 0)).Do(context.TODO())
-	if len(cs) != 4 {
-		t.Error("There should be 4 candlesticks but there is", len(cs))
+	cs, _ := s.Symbol("IOTA-USDC").EndTime(time.Unix(1257894000, 0)).Do(context.TODO())
+	if len(cs) != 1 {
+		t.Fatal("There should be 1 candlestick but there is", len(cs))
+	}
+
+	if c := TestCandleSticks[2].CandleSticks[0]; c != cs[0] {
+		t.Error("Candlestick don't correspond: should be", c, "but is", cs[0])
+	}
+}
+
+func TestMockedEndTimeDo_Latest(t *testing.T) {
+	s := newCandleStickService(TestCandleSticks)
+
+	cs, _ := s.Symbol("BTC-USDC").EndTime(time.Unix(1257894300, 0)).Limit(2).Do(context.TODO())
+	if len(cs) != 2 {
+		t.Fatal("There should be 2 candlesticks but there is", len(cs))
 	}
 
-	for i, c := range TestCandleSticks[2].CandleSticks {
+	for i, c := range TestCandleSticks[3].CandleSticks {
 		if c != cs[i] {
 			t.Error("Candlesticks", i, "don't correspond: should be", c, "but is", cs[i])
 		}
 	}
 }
 
+func TestMockedStartTimeDo(t *testing.T) {
+	s := newCandleStickService(TestCandleSticks)
+
+	cs, _ := s.StartTime(time.Unix(1257894000, 0)).Limit(3).Do(context.TODO())
+	if len(cs) != 3 {
+		t.Fatal("There should be 3 candlesticks but there is", len(cs))
+	}
+
+	expected := []models.CandleStick{
+		TestCandleSticks[2].CandleSticks[0],
+		TestCandleSticks[3].CandleSticks[0],
+		TestCandleSticks[3].CandleSticks[1],
+	}
+	for i, c := range expected {
+		if c != cs[i] {
+			t.Error("Candlesticks", i, "don't correspond: should be", c, "but is", cs[i])
+		}
+	}
+}
+
+func TestMockedDo_Sorted(t *testing.T) {
+	s := newCandleStickService(TestCandleSticks)
+
+	cs, _ := s.Do(context.TODO())
+	for i := 1; i < len(cs); i++ {
+		if cs[i].Time.Before(cs[i-1].Time) {
+			t.Error("Candlesticks", i-1, "and", i, "are not sorted")
+		}
+	}
+}
+
 func TestMockedLimitDo(t *testing.T) {
 	s := newCandleStickService(TestCandleSticks)
 
@@ -115,9 +160,12 @@ func TestMockedLimitDo_DefaultLimitTrespassed(t *testing.T) {
 
 	s := newCandleStickService(localTest)
 
-	cs, _ := s.Limit(2000).Do(context.TODO())
-	if len(cs) != DefaultCandleStickServiceLimit {
-		t.Error("There should be", DefaultCandleStickServiceLimit, "candlesticks but there is", len(cs))
+	cs, err := s.Limit(2000).Do(context.TODO())
+	if err != ErrInvalidLimit {
+		t.Error("There should be an invalid limit error but there is", err)
+	}
+	if len(cs) != 0 {
+		t.Error("There should be 0 candlesticks but there is", len(cs))
 	}
 }
 
@@ -130,7 +178,7 @@ func TestMockedAllDo(t *testing.T) {
 		t.Error("There should be 1 candlesticks but there is", len(cs))
 	}
 
-	c := TestCandleSticks[3].CandleSticks[1]
+	c := TestCandleSticks[3].CandleSticks[0]
 	if c != cs[0] {
 		t.Error("Candlestick don't correspond: should be", c, "but is", cs[0])
 	}
@@ -146,3 +194,104 @@ func TestMockedDo_Error(t *testing.T) {
 		t.Fatal("There should be an error")
 	}
 }
+
+func TestMockedDoExact(t *testing.T) {
+	localTest := []VolumeCandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1,
+		CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Open: 0.00000123, High: 0.0000013, Low: 0.0000012, Close: 0.00000125},
+			{Time: time.Unix(60, 0), Open: 0.00000125, High: 0.00000125, Low: 0.00000125, Close: 0.00000125},
+		},
+		Volumes: []float64{1.5, 2},
+	}, {
+		Symbol: "ETH-USDC", Period: models.M1,
+		CandleSticks: []models.CandleStick{{Time: time.Unix(120, 0), Open: 1, High: 1, Low: 1, Close: 1}},
+	}}
+	s := newCandleStickService(nil)
+	s.store.add(localTest)
+
+	cs, err := s.DoExact(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if len(cs) != 3 {
+		t.Fatal("There should be 3 candlesticks but there is", len(cs))
+	}
+
+	if !cs[0].CandleStick.Equal(&localTest[0].CandleSticks[0]) {
+		t.Error("Candlesticks don't correspond")
+	}
+	if cs[0].Exact.Open != "0.00000123" || cs[0].Exact.High != "0.0000013" || cs[0].Exact.Volume != "1.5" {
+		t.Error("Exact values are not correct:", cs[0].Exact)
+	}
+	if !cs[0].CloseTime.Equal(time.Unix(59, 999*int64(time.Millisecond))) {
+		t.Error("Close time is not correct:", cs[0].CloseTime)
+	}
+	if cs[2].Exact.Volume != "" {
+		t.Error("There should be no volume but there is", cs[2].Exact.Volume)
+	}
+}
+
+func TestMockedDo_UTC(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	localTest := []CandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1,
+		CandleSticks: []models.CandleStick{{Time: time.Date(2021, 6, 10, 12, 0, 0, 0, loc), Close: 1}},
+	}}
+	s := newCandleStickService(localTest)
+
+	cs, _ := s.Do(context.TODO())
+	if len(cs) != 1 {
+		t.Fatal("There should be 1 candlestick but there is", len(cs))
+	}
+	if cs[0].Time.Location() != time.UTC || !cs[0].Time.Equal(localTest[0].CandleSticks[0].Time) {
+		t.Error("Time should be in UTC but is", cs[0].Time)
+	}
+}
+
+func TestMockedValidationDo(t *testing.T) {
+	localTest := []CandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1,
+		CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Open: 1, High: 1, Low: 1, Close: 1},
+			{Time: time.Unix(60, 0), Open: 1, High: 0.5, Low: 1, Close: 1},
+		},
+	}}
+
+	s := newCandleStickService(localTest)
+	if _, err := s.Validation(interfaces.ValidationPolicyReject).Do(context.TODO()); !errors.Is(err, interfaces.ErrInvalidCandleSticks) {
+		t.Error("There should be a validation error but there is", err)
+	}
+
+	s = newCandleStickService(localTest)
+	cs, err := s.Validation(interfaces.ValidationPolicyDrop).Do(context.TODO())
+	if err != nil || len(cs) != 1 {
+		t.Error("There should be 1 candlestick but there is", len(cs), err)
+	}
+
+	s = newCandleStickService(localTest)
+	ecs, err := s.Validation(interfaces.ValidationPolicyFlag).DoExact(context.TODO())
+	if err != nil || len(ecs) != 2 || len(ecs[1].Violations) != 1 || ecs[1].Violations[0].Symbol != "BTC-USDC" {
+		t.Error("The second candlestick should be flagged:", ecs, err)
+	}
+}
+
+func TestMockedRequestDo(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	r := interfaces.CandleStickRequest{Period: models.M5}
+	btc, err := r.WithSymbol("BTC-USDC").Do(context.TODO(), m)
+	if err != nil || len(btc) != 2 || btc[0].Open != 30 {
+		t.Error("There should be 2 BTC-USDC candlesticks but there is", btc, err)
+	}
+
+	eth, err := r.WithSymbol("ETH-USDC").Do(context.TODO(), m)
+	if err != nil || len(eth) != 2 || eth[0].Open != 20 {
+		t.Error("There should be 2 ETH-USDC candlesticks but there is", eth, err)
+	}
+
+	if r.Symbol != "" {
+		t.Error("Base request should not be modified:", r)
+	}
+}
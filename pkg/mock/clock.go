@@ -0,0 +1,122 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// clock is the mocked server clock, and the offset and latency measured by
+// the last synchronization
+type clock struct {
+	mutex         sync.RWMutex
+	serverOffset  time.Duration
+	serverLatency time.Duration
+	offset        time.Duration
+	latency       time.Duration
+	lastSync      time.Time
+}
+
+func newClock() *clock {
+	return &clock{}
+}
+
+func (c *clock) setServer(offset, latency time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.serverOffset = offset
+	c.serverLatency = latency
+}
+
+func (c *clock) sync() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.offset = c.serverOffset
+	c.latency = c.serverLatency
+	c.lastSync = time.Now()
+}
+
+func (c *clock) get() (offset, latency time.Duration, lastSync time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.offset, c.latency, c.lastSync
+}
+
+// SetServerClock will set the offset between the mocked server clock and the
+// local clock (positive if the server is ahead), and the latency measured
+// by the next time synchronizations
+func (m *MockedService) SetServerClock(offset, latency time.Duration) {
+	m.clock.setServer(offset, latency)
+}
+
+// SyncTime will measure the mocked server time and update the offset applied
+// on the service clock
+func (m *MockedService) SyncTime(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.clock.sync()
+	return nil
+}
+
+// StartTimeSync will synchronize the time with the mocked server and then
+// keep refreshing it at the given interval until the context is done. The
+// interval should be positive.
+func (m *MockedService) StartTimeSync(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return interfaces.ErrTimeSyncInvalidInterval
+	}
+
+	if err := m.SyncTime(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = m.SyncTime(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ClockOffset will return the offset measured by the last time
+// synchronization
+func (m *MockedService) ClockOffset() time.Duration {
+	offset, _, _ := m.clock.get()
+	return offset
+}
+
+// Latency will return the latency measured by the last time synchronization
+func (m *MockedService) Latency() time.Duration {
+	_, latency, _ := m.clock.get()
+	return latency
+}
+
+// LastTimeSync will return the time of the last time synchronization, or zero
+// time if none happened
+func (m *MockedService) LastTimeSync() time.Time {
+	_, _, lastSync := m.clock.get()
+	return lastSync
+}
+
+// Now will return the current time for requests relative to the current
+// time, which is the local time corrected by the measured offset for the
+// mocked service
+func (m *MockedService) Now() time.Time {
+	return time.Now().Add(m.ClockOffset())
+}
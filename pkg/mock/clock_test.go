@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+func TestMockedSyncTime(t *testing.T) {
+	var s interfaces.ServiceInterface = New()
+	s.(*MockedService).SetServerClock(5*time.Second, 10*time.Millisecond)
+
+	if !s.LastTimeSync().IsZero() || s.ClockOffset() != 0 {
+		t.Error("There should be no synchronization before SyncTime")
+	}
+
+	if err := s.SyncTime(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if s.ClockOffset() != 5*time.Second || s.Latency() != 10*time.Millisecond || s.LastTimeSync().IsZero() {
+		t.Error("Clock is not synchronized correctly:", s.ClockOffset(), s.Latency(), s.LastTimeSync())
+	}
+	if d := s.Now().Sub(time.Now()); d < 4*time.Second || d > 6*time.Second {
+		t.Error("Mocked service time should be 5s ahead but is", d)
+	}
+}
+
+func TestMockedStartTimeSync(t *testing.T) {
+	m := New()
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if err := m.StartTimeSync(context.TODO(), interval); err != interfaces.ErrTimeSyncInvalidInterval {
+			t.Error("There should be an error with an interval of", interval, "but there is", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := m.StartTimeSync(ctx, 10*time.Millisecond); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	// Check that a new server offset is applied by the next synchronization
+	m.SetServerClock(time.Second, 0)
+	deadline := time.Now().Add(5 * time.Second)
+	for m.ClockOffset() != time.Second {
+		if time.Now().After(deadline) {
+			t.Fatal("Time should have been synchronized again")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
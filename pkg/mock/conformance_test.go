@@ -0,0 +1,22 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/conformance"
+	"github.com/cryptellation/binance.go/pkg/mock"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Suite{
+		Seed: func(t *testing.T, cs []mock.CandleSticks) binance.ServiceInterface {
+			m := mock.New()
+			m.AddCandleSticks(cs)
+			return m
+		},
+		// The mocked service returns the candlesticks of every symbol when
+		// no symbol is set
+		Skip: []string{"NoSymbol"},
+	}.Run(t)
+}
@@ -0,0 +1,220 @@
+package mock
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	binance "github.com/adshao/go-binance/v2"
+	"github.com/cryptellation/binance.go/pkg/adapters"
+)
+
+var (
+	// ErrFixtureFormat is returned when a fixture file has not the expected format
+	ErrFixtureFormat = errors.New("fixture error: invalid format")
+	// ErrFixtureSymbol is returned when the symbol can't be inferred from the fixture file
+	ErrFixtureSymbol = errors.New("fixture error: unknown symbol")
+	// ErrFixturePeriod is returned when the period can't be inferred from the fixture file
+	ErrFixturePeriod = errors.New("fixture error: unknown period")
+)
+
+// ReadCSVFixture will read candlesticks from a Binance klines CSV export. The
+// symbol is mandatory as klines don't have it. If the period is 0, it is
+// inferred from the klines.
+func ReadCSVFixture(r io.Reader, symbol string, period int64) (VolumeCandleSticks, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return VolumeCandleSticks{}, fmt.Errorf("%w: %s", ErrFixtureFormat, err)
+	}
+
+	kl := make([]*binance.Kline, 0, len(records))
+	for i, record := range records {
+		// Skip header if any
+		if i == 0 && len(record) > 0 {
+			if _, err := strconv.ParseInt(record[0], 10, 64); err != nil {
+				continue
+			}
+		}
+
+		k, err := adapters.KLineFromFields(record)
+		if err != nil {
+			return VolumeCandleSticks{}, fmt.Errorf("%w: line %d: %s", ErrFixtureFormat, i+1, err)
+		}
+		kl = append(kl, &k)
+	}
+
+	return klinesToFixture(kl, symbol, period)
+}
+
+// ReadJSONFixture will read candlesticks from a JSON array of klines in the
+// Binance API format. The symbol is mandatory as klines don't have it. If the
+// period is 0, it is inferred from the klines.
+func ReadJSONFixture(r io.Reader, symbol string, period int64) (VolumeCandleSticks, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var raw [][]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return VolumeCandleSticks{}, fmt.Errorf("%w: %s", ErrFixtureFormat, err)
+	}
+
+	kl := make([]*binance.Kline, len(raw))
+	for i, item := range raw {
+		fields := make([]string, len(item))
+		for j, v := range item {
+			fields[j] = fmt.Sprint(v)
+		}
+
+		k, err := adapters.KLineFromFields(fields)
+		if err != nil {
+			return VolumeCandleSticks{}, fmt.Errorf("%w: kline %d: %s", ErrFixtureFormat, i, err)
+		}
+		kl[i] = &k
+	}
+
+	return klinesToFixture(kl, symbol, period)
+}
+
+// ReadFixtureFile will read candlesticks from a CSV or JSON fixture file,
+// depending on its extension. If the symbol is empty or the period is 0, they
+// are inferred from the file name (i.e. "BTCUSDT-1m-2021-06.csv"), then from
+// the klines for the period.
+func ReadFixtureFile(path, symbol string, period int64) (VolumeCandleSticks, error) {
+	symbol, period = fixtureFileName(path, symbol, period)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return VolumeCandleSticks{}, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadCSVFixture(f, symbol, period)
+	case ".json":
+		return ReadJSONFixture(f, symbol, period)
+	default:
+		return VolumeCandleSticks{}, fmt.Errorf("%w: unknown extension %q", ErrFixtureFormat, filepath.Ext(path))
+	}
+}
+
+// LoadFixture will read candlesticks from a CSV or JSON fixture, depending
+// on its first character, and add them to the service. The symbol is
+// mandatory as klines don't have it. If the period is 0, it is inferred from
+// the klines.
+func (m *MockedService) LoadFixture(r io.Reader, symbol string, period int64) error {
+	br := bufio.NewReader(r)
+
+	var (
+		cs  VolumeCandleSticks
+		err error
+	)
+	if isJSONFixture(br) {
+		cs, err = ReadJSONFixture(br, symbol, period)
+	} else {
+		cs, err = ReadCSVFixture(br, symbol, period)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.AddVolumeCandleSticks([]VolumeCandleSticks{cs})
+	return nil
+}
+
+// LoadFixtureFiles will load the fixture files into the service, stopping at
+// the first file in error. Symbols and periods are inferred from the files
+// names as in ReadFixtureFile.
+func (m *MockedService) LoadFixtureFiles(paths ...string) error {
+	for _, p := range paths {
+		if err := m.loadFixtureFile(p); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (m *MockedService) loadFixtureFile(path string) error {
+	symbol, period := fixtureFileName(path, "", 0)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return m.LoadFixture(f, symbol, period)
+}
+
+// fixtureFileName will infer the symbol and the period from the fixture
+// file name (i.e. "BTCUSDT-1m-2021-06.csv") if they are not set
+func fixtureFileName(path, symbol string, period int64) (string, int64) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	parts := strings.Split(name, "-")
+	if symbol == "" && len(parts) > 1 {
+		symbol = parts[0]
+	}
+	if period == 0 && len(parts) > 1 {
+		period, _ = adapters.IntervalToPeriod(parts[1])
+	}
+	return symbol, period
+}
+
+// isJSONFixture will tell if the fixture is a JSON array, from its first
+// non-space character
+func isJSONFixture(r *bufio.Reader) bool {
+	for n := 1; ; n++ {
+		b, err := r.Peek(n)
+		if err != nil {
+			return false
+		}
+		if c := b[n-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c == '['
+		}
+	}
+}
+
+// klinesToFixture will convert the klines with the same conversion as the
+// real service
+func klinesToFixture(kl []*binance.Kline, symbol string, period int64) (VolumeCandleSticks, error) {
+	if symbol == "" {
+		return VolumeCandleSticks{}, ErrFixtureSymbol
+	}
+
+	// Infer period from the first kline duration
+	if period == 0 && len(kl) > 0 {
+		period = (kl[0].CloseTime - kl[0].OpenTime + 1) / 1000
+	}
+	if _, err := adapters.PeriodToInterval(period); err != nil {
+		return VolumeCandleSticks{}, ErrFixturePeriod
+	}
+
+	cs, err := adapters.KLinesToCandleSticks(kl)
+	if err != nil {
+		return VolumeCandleSticks{}, fmt.Errorf("%w: %s", ErrFixtureFormat, err)
+	}
+
+	volumes := make([]float64, len(kl))
+	for i, k := range kl {
+		if volumes[i], err = strconv.ParseFloat(k.Volume, 64); err != nil {
+			return VolumeCandleSticks{}, fmt.Errorf("%w: %s", ErrFixtureFormat, err)
+		}
+	}
+
+	return VolumeCandleSticks{
+		Symbol:       symbol,
+		Period:       period,
+		CandleSticks: cs,
+		Volumes:      volumes,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func TestReadFixtureFile_CSV(t *testing.T) {
+	cs, err := ReadFixtureFile("testdata/BTCUSDT-1m-2021-06-10.csv", "", 0)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if cs.Symbol != "BTCUSDT" || cs.Period != models.M1 {
+		t.Error("Symbol and period should be inferred but are", cs.Symbol, cs.Period)
+	}
+
+	if len(cs.CandleSticks) != 3 {
+		t.Fatal("There should be 3 candlesticks but there is", len(cs.CandleSticks))
+	}
+
+	expected := models.CandleStick{Time: time.Unix(1623283200, 0), Open: 36690.09, High: 36756, Low: 36650, Close: 36711.66}
+	if !cs.CandleSticks[0].Equal(&expected) {
+		t.Error("Candlestick should be", expected, "but is", cs.CandleSticks[0])
+	}
+
+	if v, ok := cs.Volume(0); !ok || v != 97.064465 {
+		t.Error("Volume should be 97.064465 but is", v)
+	}
+}
+
+func TestReadFixtureFile_JSON(t *testing.T) {
+	cs, err := ReadFixtureFile("testdata/ETHUSDT-5m.json", "ETH-USDT", 0)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if cs.Symbol != "ETH-USDT" || cs.Period != models.M5 {
+		t.Error("Symbol should be kept and period inferred but are", cs.Symbol, cs.Period)
+	}
+
+	if len(cs.CandleSticks) != 2 || cs.CandleSticks[1].Close != 2608.3 {
+		t.Error("Candlesticks are not correct:", cs.CandleSticks)
+	}
+}
+
+func TestReadCSVFixture_InferPeriodFromKLines(t *testing.T) {
+	data := "open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore\n" +
+		"1623283200000,1,2,0.5,1.5,10,1623286799999,15,3,5,7,0\n"
+
+	cs, err := ReadCSVFixture(strings.NewReader(data), "BTCUSDT", 0)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	if cs.Period != models.H1 || len(cs.CandleSticks) != 1 {
+		t.Error("Period should be inferred from klines but is", cs.Period)
+	}
+}
+
+func TestReadCSVFixture_Errors(t *testing.T) {
+	if _, err := ReadCSVFixture(strings.NewReader("1623283200000,1,2\n"), "BTCUSDT", models.M1); !errors.Is(err, ErrFixtureFormat) {
+		t.Error("There should be a format error but there is", err)
+	}
+
+	data := "1623283200000,error,2,0.5,1.5,10,1623283259999,15,3,5,7,0\n"
+	if _, err := ReadCSVFixture(strings.NewReader(data), "BTCUSDT", models.M1); !errors.Is(err, ErrFixtureFormat) {
+		t.Error("There should be a format error but there is", err)
+	}
+
+	data = "1623283200000,1,2,0.5,1.5,10,1623283259999,15,3,5,7,0\n"
+	if _, err := ReadCSVFixture(strings.NewReader(data), "", models.M1); !errors.Is(err, ErrFixtureSymbol) {
+		t.Error("There should be a symbol error but there is", err)
+	}
+}
+
+func TestLoadFixtureFiles(t *testing.T) {
+	m := New()
+	if err := m.LoadFixtureFiles("testdata/BTCUSDT-1m-2021-06-10.csv", "testdata/ETHUSDT-5m.json"); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	cs, err := m.NewCandleStickService().Symbol("ETHUSDT").Period(models.M5).Do(context.TODO())
+	if err != nil || len(cs) != 2 {
+		t.Error("There should be 2 candlesticks but there is", cs, err)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	m := New()
+
+	csvData := "1623283200000,1,2,0.5,1.5,10,1623283259999,15,3,5,7,0\n"
+	if err := m.LoadFixture(strings.NewReader(csvData), "BTCUSDT", models.M1); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	jsonData := ` [[1623283200000,"1","2","0.5","1.5","10",1623283499999,"15",3,"5","7","0"]]`
+	if err := m.LoadFixture(strings.NewReader(jsonData), "ETHUSDT", 0); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	cs, err := m.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(context.TODO())
+	if err != nil || len(cs) != 1 || cs[0].Close != 1.5 {
+		t.Error("There should be the CSV candlestick but there is", cs, err)
+	}
+
+	cs, err = m.NewCandleStickService().Symbol("ETHUSDT").Period(models.M5).Do(context.TODO())
+	if err != nil || len(cs) != 1 {
+		t.Error("There should be the JSON candlestick but there is", cs, err)
+	}
+
+	if err := m.LoadFixture(strings.NewReader(csvData), "", models.M1); !errors.Is(err, ErrFixtureSymbol) {
+		t.Error("There should be a symbol error but there is", err)
+	}
+}
@@ -0,0 +1,158 @@
+package mock
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cryptellation/binance.go/pkg/adapters"
+	"github.com/cryptellation/models.go"
+)
+
+const (
+	// DefaultGeneratorPrice is the initial price of generated candlesticks if none is specified
+	DefaultGeneratorPrice = 100
+	// DefaultGeneratorVolatility is the volatility of generated candlesticks if none is specified
+	DefaultGeneratorVolatility = 0.01
+	// DefaultGeneratorVolume is the mean volume of generated candlesticks if none is specified
+	DefaultGeneratorVolume = 100
+	// DefaultGeneratorSpikeSize is the size of generated spikes if none is specified
+	DefaultGeneratorSpikeSize = 0.05
+
+	// generatorSteps is the number of random walk steps simulated per candlestick
+	generatorSteps = 8
+	// generatorPrecision is the number of decimals of generated prices
+	generatorPrecision = 1e8
+)
+
+// Generator generates deterministic candlesticks following a geometric
+// Brownian motion. The same generator always generates the same candlesticks.
+// Generated candlesticks can be added to the mocked service with:
+//
+//	m.AddVolumeCandleSticks([]mock.VolumeCandleSticks{g.Generate()})
+type Generator struct {
+	Symbol string
+	Period int64
+	// Start and End are the bounds of the generated candlesticks open times,
+	// with Start included and End excluded. Open times are aligned on the period.
+	Start time.Time
+	End   time.Time
+
+	// Seed is the seed of the random walk
+	Seed int64
+	// InitialPrice is the open price of the first candlestick
+	InitialPrice float64
+	// Volatility is the standard deviation of the log return of a candlestick
+	Volatility float64
+	// Drift is the mean log return of a candlestick
+	Drift float64
+	// Volume is the mean base volume of a candlestick
+	Volume float64
+
+	// GapProbability is the probability that a candlestick is missing, the
+	// price still moves during the gap
+	GapProbability float64
+	// SpikeProbability is the probability that a candlestick has a spike
+	SpikeProbability float64
+	// SpikeSize is the relative size of a spike on the candlestick high or low
+	SpikeSize float64
+}
+
+// Generate will generate the candlesticks, with their volumes
+func (g Generator) Generate() VolumeCandleSticks {
+	g.setDefaults()
+
+	cs := VolumeCandleSticks{
+		Symbol:       g.Symbol,
+		Period:       g.Period,
+		CandleSticks: make([]models.CandleStick, 0),
+		Volumes:      make([]float64, 0),
+	}
+
+	if g.Period <= 0 {
+		return cs
+	}
+
+	random := rand.New(rand.NewSource(g.Seed))
+	price := g.InitialPrice
+
+	// Align the first open time on the period, as Binance does if supported
+	open := g.Start.Unix()
+	if aligned, err := adapters.CeilCandleTime(g.Start, g.Period); err == nil {
+		open = aligned.Unix()
+	} else if r := open % g.Period; r != 0 {
+		open += g.Period - r
+	}
+
+	for ; open < g.End.Unix(); open += g.Period {
+		c, volume := g.candle(random, price)
+		price = c.Close
+
+		if random.Float64() < g.GapProbability {
+			continue
+		}
+
+		c.Time = time.Unix(open, 0).UTC()
+		cs.CandleSticks = append(cs.CandleSticks, c)
+		cs.Volumes = append(cs.Volumes, volume)
+	}
+
+	return cs
+}
+
+func (g *Generator) setDefaults() {
+	if g.InitialPrice <= 0 {
+		g.InitialPrice = DefaultGeneratorPrice
+	}
+	if g.Volatility <= 0 {
+		g.Volatility = DefaultGeneratorVolatility
+	}
+	if g.Volume <= 0 {
+		g.Volume = DefaultGeneratorVolume
+	}
+	if g.SpikeSize <= 0 {
+		g.SpikeSize = DefaultGeneratorSpikeSize
+	}
+}
+
+// candle will simulate a candlestick opening at price, and return it with
+// its volume
+func (g Generator) candle(random *rand.Rand, price float64) (models.CandleStick, float64) {
+	c := models.CandleStick{
+		Open: roundPrice(price),
+		High: price,
+		Low:  price,
+	}
+
+	// Simulate the path of the price during the candlestick
+	sigma := g.Volatility / math.Sqrt(generatorSteps)
+	mu := (g.Drift - g.Volatility*g.Volatility/2) / generatorSteps
+	for i := 0; i < generatorSteps; i++ {
+		price *= math.Exp(mu + sigma*random.NormFloat64())
+		c.High = math.Max(c.High, price)
+		c.Low = math.Min(c.Low, price)
+	}
+	c.Close = roundPrice(price)
+
+	// Volume is log-normal around the mean and grows with the price range
+	volume := g.Volume * math.Exp(0.5*random.NormFloat64()-0.125)
+	volume *= 1 + (c.High-c.Low)/c.Open/g.Volatility/4
+
+	// A spike only moves the high or the low
+	if random.Float64() < g.SpikeProbability {
+		if random.Intn(2) == 0 {
+			c.High *= 1 + g.SpikeSize
+		} else {
+			c.Low *= 1 - g.SpikeSize
+		}
+		volume *= 3
+	}
+
+	c.High = roundPrice(c.High)
+	c.Low = roundPrice(c.Low)
+	return c, roundPrice(volume)
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(price*generatorPrecision) / generatorPrecision
+}
@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func testGenerator() Generator {
+	return Generator{
+		Symbol:           "BTC-USDC",
+		Period:           models.M15,
+		Start:            time.Unix(1600000000, 0),
+		End:              time.Unix(1600000000, 0).Add(100 * 15 * time.Minute),
+		Seed:             42,
+		SpikeProbability: 0.1,
+	}
+}
+
+func TestGenerator_Consistency(t *testing.T) {
+	cs := testGenerator().Generate()
+
+	if len(cs.CandleSticks) != 100 || len(cs.Volumes) != 100 {
+		t.Fatal("There should be 100 candlesticks and volumes but there is", len(cs.CandleSticks), len(cs.Volumes))
+	}
+
+	for i, c := range cs.CandleSticks {
+		if c.Time.Unix()%models.M15 != 0 {
+			t.Error("Candlestick", i, "time is not aligned:", c.Time)
+		}
+		if c.High < c.Open || c.High < c.Close || c.Low > c.Open || c.Low > c.Close || c.Low <= 0 {
+			t.Error("Candlestick", i, "is not consistent:", c)
+		}
+		if cs.Volumes[i] <= 0 {
+			t.Error("Volume", i, "should be positive but is", cs.Volumes[i])
+		}
+		if i > 0 && c.Open != cs.CandleSticks[i-1].Close {
+			t.Error("Candlestick", i, "should open at previous close")
+		}
+	}
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	g := testGenerator()
+	if !reflect.DeepEqual(g.Generate(), g.Generate()) {
+		t.Error("Generated candlesticks should be the same with the same seed")
+	}
+
+	other := g
+	other.Seed++
+	if reflect.DeepEqual(g.Generate(), other.Generate()) {
+		t.Error("Generated candlesticks should be different with another seed")
+	}
+}
+
+func TestGenerator_Gaps(t *testing.T) {
+	g := testGenerator()
+	g.GapProbability = 0.2
+
+	cs := g.Generate()
+	if len(cs.CandleSticks) >= 100 || len(cs.CandleSticks) == 0 {
+		t.Error("There should be missing candlesticks but there is", len(cs.CandleSticks))
+	}
+}
+
+func TestGenerator_Drift(t *testing.T) {
+	g := testGenerator()
+	g.Drift = 0.01
+
+	cs := g.Generate()
+	if last := cs.CandleSticks[len(cs.CandleSticks)-1]; last.Close <= DefaultGeneratorPrice {
+		t.Error("Price should have increased but is", last.Close)
+	}
+}
+
+func TestGenerator_MockedService(t *testing.T) {
+	m := New()
+	m.AddVolumeCandleSticks([]VolumeCandleSticks{testGenerator().Generate()})
+
+	cs, err := m.NewCandleStickService().Symbol("BTC-USDC").Period(models.M15).Limit(10).Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if len(cs) != 10 {
+		t.Error("There should be 10 candlesticks but there is", len(cs))
+	}
+}
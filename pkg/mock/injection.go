@@ -0,0 +1,225 @@
+package mock
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// ErrTooManyRequests is the error returned by Binance when the request weight
+// limit has been exceeded
+var ErrTooManyRequests = &common.APIError{Code: -1003, Message: "Too many requests; current limit is exceeded."}
+
+// Operation is the operation executed by a mocked service Do()
+type Operation string
+
+const (
+	// OpAny matches every operation
+	OpAny Operation = ""
+	// OpCandleSticks is the candlesticks request
+	OpCandleSticks Operation = "candlesticks"
+	// OpAccount is the account request
+	OpAccount Operation = "account"
+	// OpCreateOrder is the order creation request
+	OpCreateOrder Operation = "create_order"
+	// OpGetOrder is the order query request
+	OpGetOrder Operation = "get_order"
+	// OpCancelOrder is the order cancellation request
+	OpCancelOrder Operation = "cancel_order"
+	// OpCancelOpenOrders is the open orders cancellation request
+	OpCancelOpenOrders Operation = "cancel_open_orders"
+	// OpListOpenOrders is the open orders listing request
+	OpListOpenOrders Operation = "list_open_orders"
+	// OpUserDataStream is the user data stream start
+	OpUserDataStream Operation = "user_data_stream"
+)
+
+// callRule is a rule that fails the n-th call of an operation
+type callRule struct {
+	op  Operation
+	n   int
+	err error
+}
+
+// symbolRule is a rule that fails every call of an operation on a symbol
+type symbolRule struct {
+	op     Operation
+	symbol string
+	err    error
+}
+
+// call is a call of an operation, with its rank among the calls of the
+// operation (n) and among every call (total)
+type call struct {
+	op     Operation
+	symbol string
+	n      int
+	total  int
+	spent  int
+}
+
+// injector decides the failures and the latency of the mocked service calls
+type injector struct {
+	mutex sync.Mutex
+
+	calls       map[Operation]int
+	queues      map[Operation][]error
+	callRules   []callRule
+	symbolRules []symbolRule
+
+	latency time.Duration
+	jitter  time.Duration
+	random  *rand.Rand
+
+	budget int
+	spent  int
+}
+
+func newInjector() *injector {
+	return &injector{
+		calls:  make(map[Operation]int),
+		queues: make(map[Operation][]error),
+		random: rand.New(rand.NewSource(1)),
+	}
+}
+
+// inject will apply the latency and return the injected error, if any, for
+// a call of the operation on the symbol. A nil injector injects nothing.
+func (i *injector) inject(ctx context.Context, op Operation, symbol string) error {
+	if i == nil {
+		return nil
+	}
+
+	// Calls are counted when they start, so the n-th call rules do not
+	// depend on the latency of the other calls.
+	i.mutex.Lock()
+	i.calls[op]++
+	i.calls[OpAny]++
+	i.spent++
+	c := call{op: op, symbol: symbol, n: i.calls[op], total: i.calls[OpAny], spent: i.spent}
+	delay := i.delay()
+	i.mutex.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// Queued errors are only taken once the call is not cancelled anymore
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.failure(c)
+}
+
+// delay will return the latency of the next call. The lock should be held
+// by the caller.
+func (i *injector) delay() time.Duration {
+	if i.jitter <= 0 {
+		return i.latency
+	}
+	return i.latency + time.Duration(i.random.Int63n(int64(i.jitter)+1))
+}
+
+// failure will return the error of the call. The lock should be held by
+// the caller.
+func (i *injector) failure(c call) error {
+	// Request budget
+	if i.budget > 0 && c.spent > i.budget {
+		return ErrTooManyRequests
+	}
+
+	// Error queues, specific operation first
+	for _, key := range []Operation{c.op, OpAny} {
+		if queue := i.queues[key]; len(queue) > 0 {
+			i.queues[key] = queue[1:]
+			return queue[0]
+		}
+	}
+
+	// Fail on n-th call rules
+	for _, r := range i.callRules {
+		if (r.op == OpAny && r.n == c.total) || (r.op == c.op && r.n == c.n) {
+			return r.err
+		}
+	}
+
+	// Symbol rules
+	for _, r := range i.symbolRules {
+		if (r.op == OpAny || r.op == c.op) && r.symbol == c.symbol {
+			return r.err
+		}
+	}
+
+	return nil
+}
+
+// QueueErrors will add errors that will be returned, one per call and in
+// order, by the next calls of the operation (or of any operation with OpAny).
+// A nil error lets the corresponding call succeed.
+func (m *MockedService) QueueErrors(op Operation, errs ...error) {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.queues[op] = append(m.injector.queues[op], errs...)
+}
+
+// FailOnCall will make the n-th call (starting at 1) of the operation (or of
+// every operation with OpAny) return the error. Calls are counted from the
+// mocked service creation.
+func (m *MockedService) FailOnCall(op Operation, n int, err error) {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.callRules = append(m.injector.callRules, callRule{op: op, n: n, err: err})
+}
+
+// FailSymbol will make every call of the operation (or of any operation with
+// OpAny) on the symbol return the error
+func (m *MockedService) FailSymbol(op Operation, symbol string, err error) {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.symbolRules = append(m.injector.symbolRules, symbolRule{op: op, symbol: symbol, err: err})
+}
+
+// SetLatency will delay every call by the latency plus a random duration up
+// to the jitter. If the call context is done before, its error is returned.
+func (m *MockedService) SetLatency(latency, jitter time.Duration) {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.latency = latency
+	m.injector.jitter = jitter
+}
+
+// SetRequestBudget will make every call return ErrTooManyRequests once the
+// given number of calls has been spent. The spent calls are reset and a
+// budget of 0 disables the limit.
+func (m *MockedService) SetRequestBudget(budget int) {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.budget = budget
+	m.injector.spent = 0
+}
+
+// ClearInjections will remove every injected error, rule, latency and budget
+func (m *MockedService) ClearInjections() {
+	m.injector.mutex.Lock()
+	defer m.injector.mutex.Unlock()
+
+	m.injector.queues = make(map[Operation][]error)
+	m.injector.callRules = nil
+	m.injector.symbolRules = nil
+	m.injector.latency, m.injector.jitter = 0, 0
+	m.injector.budget, m.injector.spent = 0, 0
+}
@@ -0,0 +1,151 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func TestQueueErrors(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	m.QueueErrors(OpCandleSticks, errFirst, errSecond)
+
+	for i, expected := range []error{errFirst, errSecond, nil} {
+		if _, err := m.NewCandleStickService().Do(context.TODO()); err != expected {
+			t.Error("Call", i, "should return", expected, "but returned", err)
+		}
+	}
+
+	// Other operations are not affected
+	m.QueueErrors(OpCandleSticks, errFirst)
+	if _, err := m.NewAccountService().Do(context.TODO()); err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+}
+
+func TestQueueErrors_AnyOperation(t *testing.T) {
+	m := New()
+
+	errAny := errors.New("any")
+	m.QueueErrors(OpAny, nil, errAny)
+
+	if _, err := m.NewAccountService().Do(context.TODO()); err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+	if _, err := m.NewListOpenOrdersService().Symbol("BTCUSDT").Do(context.TODO()); err != errAny {
+		t.Error("There should be the queued error but there is", err)
+	}
+}
+
+func TestFailOnCall(t *testing.T) {
+	m := New()
+
+	errThird := errors.New("third")
+	m.FailOnCall(OpAccount, 3, errThird)
+
+	for i := 1; i <= 4; i++ {
+		_, err := m.NewAccountService().Do(context.TODO())
+		if i == 3 && err != errThird {
+			t.Error("Call", i, "should fail but returned", err)
+		} else if i != 3 && err != nil {
+			t.Error("Call", i, "should succeed but returned", err)
+		}
+	}
+}
+
+func TestFailOnCall_AnyOperation(t *testing.T) {
+	m := New()
+
+	errSecond := errors.New("second")
+	m.FailOnCall(OpAny, 2, errSecond)
+
+	if _, err := m.NewAccountService().Do(context.TODO()); err != nil {
+		t.Error("First call should succeed but returned", err)
+	}
+	if _, err := m.NewListOpenOrdersService().Symbol("BTCUSDT").Do(context.TODO()); err != errSecond {
+		t.Error("Second call should fail but returned", err)
+	}
+	if _, err := m.NewAccountService().Do(context.TODO()); err != nil {
+		t.Error("Third call should succeed but returned", err)
+	}
+}
+
+func TestFailSymbol(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	errSymbol := errors.New("symbol")
+	m.FailSymbol(OpAny, "ETH-USDC", errSymbol)
+
+	if _, err := m.NewCandleStickService().Symbol("ETH-USDC").Do(context.TODO()); err != errSymbol {
+		t.Error("There should be the symbol error but there is", err)
+	}
+	if _, err := m.NewCandleStickService().Symbol("BTC-USDC").Do(context.TODO()); err != nil {
+		t.Error("There should be no error but there is", err)
+	}
+}
+
+func TestSetLatency(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+	m.SetLatency(20*time.Millisecond, 10*time.Millisecond)
+
+	start := time.Now()
+	if _, err := m.NewCandleStickService().Period(models.M1).Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Error("Call should last at least 20ms but lasted", d)
+	}
+
+	// Context deadline is respected
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := m.NewCandleStickService().Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("There should be a deadline error but there is", err)
+	}
+}
+
+func TestSetLatency_KeepQueuedErrorOnCancel(t *testing.T) {
+	m := New()
+	m.SetLatency(20*time.Millisecond, 0)
+
+	errQueued := errors.New("queued")
+	m.QueueErrors(OpAccount, errQueued)
+
+	// Cancelled call does not consume the queued error
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := m.NewAccountService().Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("There should be a deadline error but there is", err)
+	}
+
+	if _, err := m.NewAccountService().Do(context.TODO()); err != errQueued {
+		t.Error("There should be the queued error but there is", err)
+	}
+}
+
+func TestSetRequestBudget(t *testing.T) {
+	m := New()
+	m.SetRequestBudget(2)
+
+	for i := 1; i <= 3; i++ {
+		_, err := m.NewAccountService().Do(context.TODO())
+		if i <= 2 && err != nil {
+			t.Error("Call", i, "should succeed but returned", err)
+		} else if i == 3 && err != ErrTooManyRequests {
+			t.Error("Call", i, "should be rate limited but returned", err)
+		}
+	}
+
+	m.ClearInjections()
+	if _, err := m.NewAccountService().Do(context.TODO()); err != nil {
+		t.Error("There should be no error after clear but there is", err)
+	}
+}
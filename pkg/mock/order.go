@@ -0,0 +1,661 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+var (
+	// ErrOrderDoesNotExist is the error returned by Binance when a queried order does not exist
+	ErrOrderDoesNotExist = &common.APIError{Code: -2013, Message: "Order does not exist."}
+	// ErrUnknownOrder is the error returned by Binance when a canceled order is not open
+	ErrUnknownOrder = &common.APIError{Code: -2011, Message: "Unknown order sent."}
+	// ErrDuplicateOrder is the error returned by Binance when an open order already has the same client ID
+	ErrDuplicateOrder = &common.APIError{Code: -2010, Message: "Duplicate order sent."}
+)
+
+// orderHooks are called by the orders state, with its lock held, when an
+// order is created, filled or canceled
+type orderHooks interface {
+	// orderCreated is called before the order is stored, the order is
+	// rejected if an error is returned
+	orderCreated(order *interfaces.Order) error
+	// orderFilled is called to fill the quantity (positive and limited to the
+	// remaining one) of the order at the price, instead of the orders state
+	orderFilled(order *interfaces.Order, quantity, price float64) error
+	// orderCanceled is called after the order has been canceled
+	orderCanceled(order *interfaces.Order)
+}
+
+// orders is the orders state shared between the mocked service and its
+// order services
+type orders struct {
+	mutex  sync.RWMutex
+	lastID int64
+	list   []*interfaces.Order
+	hooks  orderHooks
+	// lastPrice returns the last known price of a symbol, used to fill market
+	// orders when there is no hooks
+	lastPrice func(symbol string) (float64, bool)
+}
+
+func newOrders() *orders {
+	return &orders{}
+}
+
+// find will return the order corresponding to the symbol and the ID or the
+// client ID. The lock should be held by the caller.
+func (o *orders) find(symbol string, id int64, clientOrderID string) *interfaces.Order {
+	for _, order := range o.list {
+		if order.Symbol != symbol {
+			continue
+		}
+
+		if id != 0 && order.ID != id {
+			continue
+		}
+
+		if clientOrderID != "" && order.ClientOrderID != clientOrderID {
+			continue
+		}
+
+		return order
+	}
+	return nil
+}
+
+func (o *orders) create(r interfaces.OrderRequest) (interfaces.Order, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// Check that client order ID is not already used by an open order
+	if r.ClientOrderID != "" {
+		if order := o.find(r.Symbol, 0, r.ClientOrderID); order != nil && order.Status.IsOpen() {
+			return interfaces.Order{}, ErrDuplicateOrder
+		}
+	}
+
+	o.lastID++
+	now := time.Now()
+	order := &interfaces.Order{
+		Symbol:        r.Symbol,
+		ID:            o.lastID,
+		ClientOrderID: r.ClientOrderID,
+		Side:          r.Side,
+		Type:          r.Type,
+		Status:        interfaces.OrderStatusNew,
+		TimeInForce:   r.TimeInForce,
+		Price:         r.Price,
+		StopPrice:     r.StopPrice,
+		Quantity:      r.Quantity,
+		Time:          now,
+		UpdateTime:    now,
+	}
+
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = fmt.Sprintf("mock-%d", order.ID)
+	}
+
+	if o.hooks != nil {
+		if err := o.hooks.orderCreated(order); err != nil {
+			o.lastID--
+			return interfaces.Order{}, err
+		}
+	}
+
+	o.list = append(o.list, order)
+
+	// Market orders are filled at once at the last known price, as on Binance
+	if o.hooks == nil && o.lastPrice != nil && order.Type == interfaces.OrderTypeMarket {
+		if price, ok := o.lastPrice(order.Symbol); ok {
+			o.execute(order, order.Quantity, price)
+		}
+	}
+
+	return *order, nil
+}
+
+func (o *orders) get(symbol string, id int64, clientOrderID string) (interfaces.Order, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	order := o.find(symbol, id, clientOrderID)
+	if order == nil {
+		return interfaces.Order{}, ErrOrderDoesNotExist
+	}
+
+	return *order, nil
+}
+
+func (o *orders) cancel(symbol string, id int64, clientOrderID string) (interfaces.Order, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	order := o.find(symbol, id, clientOrderID)
+	if order == nil || !order.Status.IsOpen() {
+		return interfaces.Order{}, ErrUnknownOrder
+	}
+
+	order.Status = interfaces.OrderStatusCanceled
+	order.UpdateTime = time.Now()
+	if o.hooks != nil {
+		o.hooks.orderCanceled(order)
+	}
+
+	return *order, nil
+}
+
+func (o *orders) cancelOpen(symbol string) ([]interfaces.Order, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	canceled := make([]interfaces.Order, 0)
+	for _, order := range o.list {
+		if order.Symbol != symbol || !order.Status.IsOpen() {
+			continue
+		}
+
+		order.Status = interfaces.OrderStatusCanceled
+		order.UpdateTime = time.Now()
+		if o.hooks != nil {
+			o.hooks.orderCanceled(order)
+		}
+
+		canceled = append(canceled, *order)
+	}
+
+	if len(canceled) == 0 {
+		return nil, ErrUnknownOrder
+	}
+
+	return canceled, nil
+}
+
+func (o *orders) listOpen(symbol string) []interfaces.Order {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	open := make([]interfaces.Order, 0)
+	for _, order := range o.list {
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+
+		if order.Status.IsOpen() {
+			open = append(open, *order)
+		}
+	}
+
+	return open
+}
+
+func (o *orders) fill(id int64, quantity, price float64) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var order *interfaces.Order
+	for _, ord := range o.list {
+		if ord.ID == id {
+			order = ord
+			break
+		}
+	}
+
+	if order == nil || !order.Status.IsOpen() {
+		return ErrUnknownOrder
+	}
+
+	if quantity <= 0 {
+		return interfaces.ErrOrderInvalidQuantity
+	}
+
+	if o.hooks != nil {
+		if remaining := order.Quantity - order.ExecutedQuantity; quantity > remaining {
+			quantity = remaining
+		}
+		return o.hooks.orderFilled(order, quantity, price)
+	}
+
+	o.execute(order, quantity, price)
+	return nil
+}
+
+// execute will fill the quantity (limited to the remaining one) of the order
+// at the price. The lock should be held by the caller.
+func (o *orders) execute(order *interfaces.Order, quantity, price float64) {
+	if remaining := order.Quantity - order.ExecutedQuantity; quantity > remaining {
+		quantity = remaining
+	}
+
+	order.ExecutedQuantity += quantity
+	order.CumulativeQuoteQuantity += quantity * price
+	order.UpdateTime = time.Now()
+	if order.ExecutedQuantity >= order.Quantity {
+		order.Status = interfaces.OrderStatusFilled
+	} else {
+		order.Status = interfaces.OrderStatusPartiallyFilled
+	}
+}
+
+func (o *orders) all() []interfaces.Order {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	list := make([]interfaces.Order, len(o.list))
+	for i, order := range o.list {
+		list[i] = *order
+	}
+
+	return list
+}
+
+// CreateOrderService is the mocked service for order creation
+type CreateOrderService struct {
+	orders *orders
+
+	request  interfaces.OrderRequest
+	err      error
+	injector *injector
+	calls    *calls
+}
+
+func newCreateOrderService(o *orders) *CreateOrderService {
+	return &CreateOrderService{
+		orders: o,
+	}
+}
+
+// Do will execute a request for order creation
+func (m *CreateOrderService) Do(ctx context.Context) (interfaces.Order, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{
+		Operation:     OpCreateOrder,
+		Symbol:        m.request.Symbol,
+		ClientOrderID: m.request.ClientOrderID,
+		OrderRequest:  m.request,
+	}, start, countOrder(err), err)
+	return res, err
+}
+
+// DoExact will execute a request for order creation as Do, with the exact
+// decimal values of the order
+func (m *CreateOrderService) DoExact(ctx context.Context) (interfaces.ExactOrder, error) {
+	o, err := m.Do(ctx)
+	if err != nil {
+		return interfaces.ExactOrder{}, err
+	}
+	return interfaces.NewExactOrder(o), nil
+}
+
+func (m *CreateOrderService) do(ctx context.Context) (interfaces.Order, error) {
+	if m.err != nil {
+		return interfaces.Order{}, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpCreateOrder, m.request.Symbol); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	if err := m.request.Validate(); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	return m.orders.create(m.request.WithDefaults())
+}
+
+// Symbol will specify a symbol for next order creation
+func (m *CreateOrderService) Symbol(symbol string) interfaces.CreateOrderServiceInterface {
+	m.request.Symbol = symbol
+	return m
+}
+
+// Side will specify a side for next order creation
+func (m *CreateOrderService) Side(side interfaces.OrderSide) interfaces.CreateOrderServiceInterface {
+	m.request.Side = side
+	return m
+}
+
+// Type will specify a type for next order creation
+func (m *CreateOrderService) Type(orderType interfaces.OrderType) interfaces.CreateOrderServiceInterface {
+	m.request.Type = orderType
+	return m
+}
+
+// Quantity will specify a base asset quantity for next order creation
+func (m *CreateOrderService) Quantity(quantity float64) interfaces.CreateOrderServiceInterface {
+	m.request.Quantity = quantity
+	return m
+}
+
+// Price will specify a limit price for next order creation
+func (m *CreateOrderService) Price(price float64) interfaces.CreateOrderServiceInterface {
+	m.request.Price = price
+	return m
+}
+
+// StopPrice will specify a stop price for next order creation
+func (m *CreateOrderService) StopPrice(stopPrice float64) interfaces.CreateOrderServiceInterface {
+	m.request.StopPrice = stopPrice
+	return m
+}
+
+// TimeInForce will specify a time in force for next order creation
+func (m *CreateOrderService) TimeInForce(timeInForce interfaces.TimeInForce) interfaces.CreateOrderServiceInterface {
+	m.request.TimeInForce = timeInForce
+	return m
+}
+
+// ClientOrderID will specify a custom ID for next order creation
+func (m *CreateOrderService) ClientOrderID(id string) interfaces.CreateOrderServiceInterface {
+	m.request.ClientOrderID = id
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *CreateOrderService) SetError(err error) {
+	m.err = err
+}
+
+// GetOrderService is the mocked service for order query
+type GetOrderService struct {
+	orders *orders
+
+	symbol        string
+	id            int64
+	clientOrderID string
+	err           error
+	injector      *injector
+	calls         *calls
+}
+
+func newGetOrderService(o *orders) *GetOrderService {
+	return &GetOrderService{
+		orders: o,
+	}
+}
+
+// Do will execute a request for an order
+func (m *GetOrderService) Do(ctx context.Context) (interfaces.Order, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{
+		Operation:     OpGetOrder,
+		Symbol:        m.symbol,
+		OrderID:       m.id,
+		ClientOrderID: m.clientOrderID,
+	}, start, countOrder(err), err)
+	return res, err
+}
+
+// DoExact will execute a request for an order as Do, with the exact
+// decimal values of the order
+func (m *GetOrderService) DoExact(ctx context.Context) (interfaces.ExactOrder, error) {
+	o, err := m.Do(ctx)
+	if err != nil {
+		return interfaces.ExactOrder{}, err
+	}
+	return interfaces.NewExactOrder(o), nil
+}
+
+func (m *GetOrderService) do(ctx context.Context) (interfaces.Order, error) {
+	if m.err != nil {
+		return interfaces.Order{}, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpGetOrder, m.symbol); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	if err := interfaces.CheckOrderIdentifiers(m.symbol, m.id, m.clientOrderID); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	return m.orders.get(m.symbol, m.id, m.clientOrderID)
+}
+
+// Symbol will specify the symbol of the requested order
+func (m *GetOrderService) Symbol(symbol string) interfaces.GetOrderServiceInterface {
+	m.symbol = symbol
+	return m
+}
+
+// OrderID will specify the ID of the requested order
+func (m *GetOrderService) OrderID(id int64) interfaces.GetOrderServiceInterface {
+	m.id = id
+	return m
+}
+
+// ClientOrderID will specify the client ID of the requested order
+func (m *GetOrderService) ClientOrderID(id string) interfaces.GetOrderServiceInterface {
+	m.clientOrderID = id
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *GetOrderService) SetError(err error) {
+	m.err = err
+}
+
+// CancelOrderService is the mocked service for order cancellation
+type CancelOrderService struct {
+	orders *orders
+
+	symbol        string
+	id            int64
+	clientOrderID string
+	err           error
+	injector      *injector
+	calls         *calls
+}
+
+func newCancelOrderService(o *orders) *CancelOrderService {
+	return &CancelOrderService{
+		orders: o,
+	}
+}
+
+// Do will execute a request for order cancellation
+func (m *CancelOrderService) Do(ctx context.Context) (interfaces.Order, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{
+		Operation:     OpCancelOrder,
+		Symbol:        m.symbol,
+		OrderID:       m.id,
+		ClientOrderID: m.clientOrderID,
+	}, start, countOrder(err), err)
+	return res, err
+}
+
+// DoExact will execute a request for order cancellation as Do, with the exact
+// decimal values of the order
+func (m *CancelOrderService) DoExact(ctx context.Context) (interfaces.ExactOrder, error) {
+	o, err := m.Do(ctx)
+	if err != nil {
+		return interfaces.ExactOrder{}, err
+	}
+	return interfaces.NewExactOrder(o), nil
+}
+
+func (m *CancelOrderService) do(ctx context.Context) (interfaces.Order, error) {
+	if m.err != nil {
+		return interfaces.Order{}, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpCancelOrder, m.symbol); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	if err := interfaces.CheckOrderIdentifiers(m.symbol, m.id, m.clientOrderID); err != nil {
+		return interfaces.Order{}, err
+	}
+
+	return m.orders.cancel(m.symbol, m.id, m.clientOrderID)
+}
+
+// Symbol will specify the symbol of the order to cancel
+func (m *CancelOrderService) Symbol(symbol string) interfaces.CancelOrderServiceInterface {
+	m.symbol = symbol
+	return m
+}
+
+// OrderID will specify the ID of the order to cancel
+func (m *CancelOrderService) OrderID(id int64) interfaces.CancelOrderServiceInterface {
+	m.id = id
+	return m
+}
+
+// ClientOrderID will specify the client ID of the order to cancel
+func (m *CancelOrderService) ClientOrderID(id string) interfaces.CancelOrderServiceInterface {
+	m.clientOrderID = id
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *CancelOrderService) SetError(err error) {
+	m.err = err
+}
+
+// CancelOpenOrdersService is the mocked service for cancellation of every
+// open orders on a symbol
+type CancelOpenOrdersService struct {
+	orders *orders
+
+	symbol   string
+	err      error
+	injector *injector
+	calls    *calls
+}
+
+func newCancelOpenOrdersService(o *orders) *CancelOpenOrdersService {
+	return &CancelOpenOrdersService{
+		orders: o,
+	}
+}
+
+// Do will execute a request for open orders cancellation
+func (m *CancelOpenOrdersService) Do(ctx context.Context) ([]interfaces.Order, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{Operation: OpCancelOpenOrders, Symbol: m.symbol}, start, len(res), err)
+	return res, err
+}
+
+// DoExact will execute a request for open orders cancellation as Do, with the exact
+// decimal values of the orders
+func (m *CancelOpenOrdersService) DoExact(ctx context.Context) ([]interfaces.ExactOrder, error) {
+	return exactOrders(m.Do(ctx))
+}
+
+func (m *CancelOpenOrdersService) do(ctx context.Context) ([]interfaces.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpCancelOpenOrders, m.symbol); err != nil {
+		return nil, err
+	}
+
+	if m.symbol == "" {
+		return nil, interfaces.ErrOrderNoSymbol
+	}
+
+	return m.orders.cancelOpen(m.symbol)
+}
+
+// Symbol will specify the symbol of the orders to cancel
+func (m *CancelOpenOrdersService) Symbol(symbol string) interfaces.CancelOpenOrdersServiceInterface {
+	m.symbol = symbol
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *CancelOpenOrdersService) SetError(err error) {
+	m.err = err
+}
+
+// ListOpenOrdersService is the mocked service for open orders listing
+type ListOpenOrdersService struct {
+	orders *orders
+
+	symbol   string
+	err      error
+	injector *injector
+	calls    *calls
+}
+
+func newListOpenOrdersService(o *orders) *ListOpenOrdersService {
+	return &ListOpenOrdersService{
+		orders: o,
+	}
+}
+
+// Do will execute a request for open orders
+func (m *ListOpenOrdersService) Do(ctx context.Context) ([]interfaces.Order, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{Operation: OpListOpenOrders, Symbol: m.symbol}, start, len(res), err)
+	return res, err
+}
+
+// DoExact will execute a request for open orders as Do, with the exact
+// decimal values of the orders
+func (m *ListOpenOrdersService) DoExact(ctx context.Context) ([]interfaces.ExactOrder, error) {
+	return exactOrders(m.Do(ctx))
+}
+
+func (m *ListOpenOrdersService) do(ctx context.Context) ([]interfaces.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpListOpenOrders, m.symbol); err != nil {
+		return nil, err
+	}
+
+	return m.orders.listOpen(m.symbol), nil
+}
+
+// Symbol will specify the symbol of the open orders, if none is specified then
+// open orders from every symbols will be listed
+func (m *ListOpenOrdersService) Symbol(symbol string) interfaces.ListOpenOrdersServiceInterface {
+	m.symbol = symbol
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *ListOpenOrdersService) SetError(err error) {
+	m.err = err
+}
+
+// countOrder will return the number of orders returned by a single order request
+// exactOrders will return the orders with their exact decimal values
+func exactOrders(orders []interfaces.Order, err error) ([]interfaces.ExactOrder, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	exact := make([]interfaces.ExactOrder, len(orders))
+	for i, o := range orders {
+		exact[i] = interfaces.NewExactOrder(o)
+	}
+	return exact, nil
+}
+
+func countOrder(err error) int {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,310 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+func TestMockedCreateOrderDo(t *testing.T) {
+	m := New()
+
+	o, err := m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeLimit).
+		Quantity(1).
+		Price(10).
+		Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.ID == 0 || o.ClientOrderID == "" {
+		t.Error("Order should have identifiers:", o)
+	}
+
+	if o.Status != interfaces.OrderStatusNew || o.TimeInForce != interfaces.TimeInForceGTC {
+		t.Error("Order is not correct:", o)
+	}
+
+	if len(m.Orders()) != 1 {
+		t.Error("There should be 1 order, but there is", len(m.Orders()))
+	}
+}
+
+func TestMockedCreateOrderDoExact(t *testing.T) {
+	m := New()
+
+	o, err := m.NewCreateOrderService().
+		Symbol("SHIBUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeLimit).
+		Quantity(100000000).
+		Price(0.00000001).
+		DoExact(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.Exact.Price != "0.00000001" || o.Exact.Quantity != "100000000" {
+		t.Error("Order decimals are not correct:", o.Exact)
+	}
+
+	orders, err := m.NewListOpenOrdersService().Symbol("SHIBUSDT").DoExact(context.TODO())
+	if err != nil || len(orders) != 1 || orders[0].Exact.Price != "0.00000001" {
+		t.Error("Open orders should have their decimals but are", orders, err)
+	}
+}
+
+func TestMockedCreateOrderDo_Invalid(t *testing.T) {
+	m := New()
+
+	_, err := m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeMarket).
+		Quantity(1).
+		Price(10).
+		Do(context.TODO())
+	if err != interfaces.ErrOrderInvalidPrice {
+		t.Error("There should be an error on price, but there is", err)
+	}
+
+	if len(m.Orders()) != 0 {
+		t.Error("There should be no order, but there is", len(m.Orders()))
+	}
+}
+
+func TestMockedCreateOrderDo_DuplicateClientOrderID(t *testing.T) {
+	m := New()
+
+	for i := 0; i < 2; i++ {
+		_, err := m.NewCreateOrderService().
+			Symbol("BTCUSDT").
+			Side(interfaces.OrderSideBuy).
+			Type(interfaces.OrderTypeMarket).
+			Quantity(1).
+			ClientOrderID("client").
+			Do(context.TODO())
+		if i == 0 && err != nil {
+			t.Fatal("There should be no error:", err)
+		} else if i == 1 && err != ErrDuplicateOrder {
+			t.Error("There should be a duplicate order error, but there is", err)
+		}
+	}
+}
+
+func TestMockedGetOrderDo(t *testing.T) {
+	m := New()
+	created, _ := m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeMarket).
+		Quantity(1).
+		Do(context.TODO())
+
+	o, err := m.NewGetOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if o != created {
+		t.Error("Order don't correspond: should be", created, "but is", o)
+	}
+
+	o, err = m.NewGetOrderService().Symbol("BTCUSDT").ClientOrderID(created.ClientOrderID).Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if o != created {
+		t.Error("Order don't correspond: should be", created, "but is", o)
+	}
+}
+
+func TestMockedGetOrderDo_Errors(t *testing.T) {
+	m := New()
+
+	if _, err := m.NewGetOrderService().OrderID(1).Do(context.TODO()); err != interfaces.ErrOrderNoSymbol {
+		t.Error("There should be an error on symbol, but there is", err)
+	}
+
+	if _, err := m.NewGetOrderService().Symbol("BTCUSDT").Do(context.TODO()); err != interfaces.ErrOrderNoIdentifier {
+		t.Error("There should be an error on identifier, but there is", err)
+	}
+
+	if _, err := m.NewGetOrderService().Symbol("BTCUSDT").OrderID(1).Do(context.TODO()); err != ErrOrderDoesNotExist {
+		t.Error("There should be an error on inexistant order, but there is", err)
+	}
+}
+
+func TestMockedCancelOrderDo(t *testing.T) {
+	m := New()
+	created, _ := m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideSell).
+		Type(interfaces.OrderTypeLimit).
+		Quantity(1).
+		Price(10).
+		Do(context.TODO())
+
+	o, err := m.NewCancelOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if o.Status != interfaces.OrderStatusCanceled {
+		t.Error("Order should be canceled but is", o.Status)
+	}
+
+	if _, err = m.NewCancelOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO()); err != ErrUnknownOrder {
+		t.Error("There should be an error on already canceled order, but there is", err)
+	}
+}
+
+func TestMockedCancelOpenOrdersDo(t *testing.T) {
+	m := New()
+	for _, symbol := range []string{"BTCUSDT", "BTCUSDT", "ETHUSDT"} {
+		_, _ = m.NewCreateOrderService().
+			Symbol(symbol).
+			Side(interfaces.OrderSideBuy).
+			Type(interfaces.OrderTypeLimit).
+			Quantity(1).
+			Price(10).
+			Do(context.TODO())
+	}
+
+	orders, err := m.NewCancelOpenOrdersService().Symbol("BTCUSDT").Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if len(orders) != 2 {
+		t.Error("There should be 2 canceled orders, but there is", len(orders))
+	}
+
+	if _, err := m.NewCancelOpenOrdersService().Symbol("BTCUSDT").Do(context.TODO()); err != ErrUnknownOrder {
+		t.Error("There should be an error when there is no open order, but there is", err)
+	}
+
+	if _, err := m.NewCancelOpenOrdersService().Do(context.TODO()); err != interfaces.ErrOrderNoSymbol {
+		t.Error("There should be an error on symbol, but there is", err)
+	}
+}
+
+func TestMockedListOpenOrdersDo(t *testing.T) {
+	m := New()
+	for _, symbol := range []string{"BTCUSDT", "BTCUSDT", "ETHUSDT"} {
+		_, _ = m.NewCreateOrderService().
+			Symbol(symbol).
+			Side(interfaces.OrderSideBuy).
+			Type(interfaces.OrderTypeLimit).
+			Quantity(1).
+			Price(10).
+			Do(context.TODO())
+	}
+	_ = m.FillOrder(1, 1, 10)
+
+	orders, _ := m.NewListOpenOrdersService().Do(context.TODO())
+	if len(orders) != 2 {
+		t.Error("There should be 2 open orders, but there is", len(orders))
+	}
+
+	orders, _ = m.NewListOpenOrdersService().Symbol("ETHUSDT").Do(context.TODO())
+	if len(orders) != 1 {
+		t.Error("There should be 1 open order, but there is", len(orders))
+	}
+}
+
+func TestMockedFillOrder(t *testing.T) {
+	m := New()
+	created, _ := m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeLimit).
+		Quantity(2).
+		Price(10).
+		Do(context.TODO())
+
+	if err := m.FillOrder(created.ID, 0, 10); err != interfaces.ErrOrderInvalidQuantity {
+		t.Error("There should be an error on quantity, but there is", err)
+	}
+	if o, _ := m.NewGetOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO()); o.Status != interfaces.OrderStatusNew {
+		t.Error("Order should not be filled with no quantity:", o)
+	}
+
+	if err := m.FillOrder(created.ID, 1, 10); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	o, _ := m.NewGetOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO())
+	if o.Status != interfaces.OrderStatusPartiallyFilled || o.ExecutedQuantity != 1 || o.CumulativeQuoteQuantity != 10 {
+		t.Error("Order is not partially filled correctly:", o)
+	}
+
+	if err := m.FillOrder(created.ID, 5, 10); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	o, _ = m.NewGetOrderService().Symbol("BTCUSDT").OrderID(created.ID).Do(context.TODO())
+	if o.Status != interfaces.OrderStatusFilled || o.ExecutedQuantity != 2 || o.CumulativeQuoteQuantity != 20 {
+		t.Error("Order is not filled correctly:", o)
+	}
+
+	if err := m.FillOrder(created.ID, 1, 10); err != ErrUnknownOrder {
+		t.Error("There should be an error on filled order, but there is", err)
+	}
+}
+
+func TestMockedOrders_Error(t *testing.T) {
+	m := New()
+	m.NextError(errors.New("Some error"))
+
+	if _, err := m.NewCreateOrderService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on order creation service")
+	}
+	if _, err := m.NewGetOrderService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on order query service")
+	}
+	if _, err := m.NewCancelOrderService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on order cancellation service")
+	}
+	if _, err := m.NewCancelOpenOrdersService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on open orders cancellation service")
+	}
+	if _, err := m.NewListOpenOrdersService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on open orders listing service")
+	}
+}
+
+func TestMockedCreateOrderDo_MarketFill(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	o, err := m.NewCreateOrderService().
+		Symbol("ETH-USDC").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeMarket).
+		Quantity(2).
+		Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.Status != interfaces.OrderStatusFilled || o.ExecutedQuantity != 2 || o.CumulativeQuoteQuantity != 50 {
+		t.Error("Market order should be filled at the last close price:", o)
+	}
+
+	o, err = m.NewCreateOrderService().
+		Symbol("BTCUSDT").
+		Side(interfaces.OrderSideBuy).
+		Type(interfaces.OrderTypeMarket).
+		Quantity(2).
+		Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	if o.Status != interfaces.OrderStatusNew {
+		t.Error("Market order without price should stay new:", o)
+	}
+}
@@ -0,0 +1,540 @@
+package mock
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/models.go"
+)
+
+var (
+	// ErrInsufficientBalance is the error returned by Binance when there is not
+	// enough free balance to create an order
+	ErrInsufficientBalance = &common.APIError{Code: -2010, Message: "Account has insufficient balance for requested action."}
+	// ErrPaperUnknownSymbol is returned when the assets of a symbol can't be determined
+	ErrPaperUnknownSymbol = errors.New("paper exchange error: unknown assets for symbol")
+	// ErrPaperNoPrice is returned when a market order is created without any
+	// closed candlestick to estimate its price
+	ErrPaperNoPrice = errors.New("paper exchange error: no price for symbol")
+	// ErrPaperTimeBackward is returned when trying to move the time backward
+	ErrPaperTimeBackward = errors.New("paper exchange error: time can't go backward")
+)
+
+// paperOrder is the paper exchange state of an order
+type paperOrder struct {
+	triggered bool
+	asset     string
+	locked    float64
+}
+
+// paperCandle is a candlestick processed by the paper exchange
+type paperCandle struct {
+	symbol    string
+	candle    models.CandleStick
+	volume    float64
+	hasVolume bool
+}
+
+// PaperExchange is a mocked service that simulates trading: orders lock the
+// account balances when created and are filled against the candlesticks of
+// the mocked service as time advances.
+//
+// Candlesticks are processed once closed: market orders are filled at the
+// open price of the next processed candlestick, or expired if the account
+// can not pay a buy at this price, limit orders when the price
+// reaches their limit, and stop-loss and take-profit orders become limit
+// orders when the price reaches their stop. If the candlesticks have volumes,
+// the quantity filled on each candlestick is limited to a ratio of its volume,
+// leaving the orders partially filled. Time in force is not simulated: every
+// limit order stays open until filled or canceled.
+type PaperExchange struct {
+	*MockedService
+
+	// These fields are protected by the orders lock
+	period      int64
+	now         time.Time
+	volumeRatio float64
+	symbols     map[string][2]string
+	states      map[int64]*paperOrder
+
+	eventsMutex sync.Mutex
+	events      []interfaces.UserDataEvent
+	dispatching bool
+}
+
+// NewPaperExchange will create a paper exchange that fills orders against the
+// candlesticks of the given period, starting at the given time
+func NewPaperExchange(period int64, start time.Time) *PaperExchange {
+	p := &PaperExchange{
+		MockedService: New(),
+		period:        period,
+		now:           start,
+		volumeRatio:   1,
+		symbols:       make(map[string][2]string),
+		states:        make(map[int64]*paperOrder),
+	}
+	p.orders.hooks = p
+	return p
+}
+
+// SetFeeRate will set the rate of the fees taken on each fill, for both maker
+// and taker sides. Fees are taken on the received asset.
+func (p *PaperExchange) SetFeeRate(rate float64) {
+	p.SetCommissions(rate, rate)
+}
+
+// SetVolumeRatio will set the ratio of each candlestick volume that can be
+// filled by the orders. A ratio of 1 (default) allows the orders to consume
+// the whole candlestick volume.
+func (p *PaperExchange) SetVolumeRatio(ratio float64) {
+	p.orders.mutex.Lock()
+	defer p.orders.mutex.Unlock()
+
+	p.volumeRatio = ratio
+}
+
+// SetSymbolAssets will set the base and quote assets of a symbol. If a
+// symbol is not set, its assets are determined by splitting it on a dash
+// (i.e. "BTC-USDC").
+func (p *PaperExchange) SetSymbolAssets(symbol, base, quote string) {
+	p.orders.mutex.Lock()
+	defer p.orders.mutex.Unlock()
+
+	p.symbols[symbol] = [2]string{base, quote}
+}
+
+// Now will return the current time of the paper exchange
+func (p *PaperExchange) Now() time.Time {
+	p.orders.mutex.RLock()
+	defer p.orders.mutex.RUnlock()
+
+	return p.now
+}
+
+// Advance will move the paper exchange time forward and fill the open orders
+// against every candlesticks closed in the meantime
+func (p *PaperExchange) Advance(to time.Time) error {
+	p.orders.mutex.Lock()
+	defer p.orders.mutex.Unlock()
+
+	if to.Before(p.now) {
+		return ErrPaperTimeBackward
+	}
+
+	for _, pc := range p.closedCandles(p.now, to) {
+		p.now = pc.candle.Time.Add(time.Duration(p.period) * time.Second)
+		p.processCandle(pc)
+	}
+	p.now = to
+
+	return nil
+}
+
+// assets will return the base and quote assets of a symbol
+func (p *PaperExchange) assets(symbol string) (base, quote string, err error) {
+	if a, ok := p.symbols[symbol]; ok {
+		return a[0], a[1], nil
+	}
+
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrPaperUnknownSymbol
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// closedCandles will return the candlesticks with the paper exchange period
+// that closed after from and before (or at) to, in chronological order
+func (p *PaperExchange) closedCandles(from, to time.Time) []paperCandle {
+	duration := time.Duration(p.period) * time.Second
+
+	candles := make([]paperCandle, 0)
+	for _, cs := range p.candles.snapshot() {
+		if cs.Period != p.period {
+			continue
+		}
+
+		for i, c := range cs.CandleSticks {
+			closeTime := c.Time.Add(duration)
+			if !closeTime.After(from) || closeTime.After(to) {
+				continue
+			}
+
+			volume, hasVolume := cs.Volume(i)
+			candles = append(candles, paperCandle{
+				symbol:    cs.Symbol,
+				candle:    c,
+				volume:    volume,
+				hasVolume: hasVolume,
+			})
+		}
+	}
+
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].candle.Time.Before(candles[j].candle.Time)
+	})
+
+	return candles
+}
+
+// lastPrice will return the close price of the last closed candlestick of the symbol
+func (p *PaperExchange) lastPrice(symbol string) (float64, bool) {
+	duration := time.Duration(p.period) * time.Second
+
+	var last *models.CandleStick
+	for _, cs := range p.candles.snapshot() {
+		if cs.Symbol != symbol || cs.Period != p.period {
+			continue
+		}
+
+		for i, c := range cs.CandleSticks {
+			if c.Time.Add(duration).After(p.now) {
+				continue
+			}
+
+			if last == nil || c.Time.After(last.Time) {
+				last = &cs.CandleSticks[i]
+			}
+		}
+	}
+
+	if last == nil {
+		return 0, false
+	}
+	return last.Close, true
+}
+
+func (p *PaperExchange) processCandle(pc paperCandle) {
+	budget := math.Inf(1)
+	if pc.hasVolume {
+		budget = pc.volume * p.volumeRatio
+	}
+
+	for _, order := range p.orders.list {
+		if budget <= 0 {
+			return
+		}
+
+		if order.Symbol != pc.symbol || !order.Status.IsOpen() {
+			continue
+		}
+
+		price, maker, ok := p.executionPrice(order, pc.candle)
+		if !ok {
+			continue
+		}
+
+		quantity := math.Min(order.Quantity-order.ExecutedQuantity, budget)
+		if !p.affordable(order, quantity, price) {
+			p.expire(order)
+			continue
+		}
+
+		budget -= quantity
+		p.execute(order, quantity, price, maker)
+	}
+}
+
+// executionPrice will return the price at which the order is filled on the
+// candlestick, and if it is filled as a maker
+func (p *PaperExchange) executionPrice(order *interfaces.Order, c models.CandleStick) (price float64, maker, ok bool) {
+	switch order.Type {
+	case interfaces.OrderTypeMarket:
+		return c.Open, false, true
+	case interfaces.OrderTypeLimit:
+		return limitExecutionPrice(order, c)
+	case interfaces.OrderTypeStopLossLimit, interfaces.OrderTypeTakeProfitLimit:
+		state := p.states[order.ID]
+		if state.triggered {
+			return limitExecutionPrice(order, c)
+		}
+
+		if !stopTriggered(order, c) {
+			return 0, false, false
+		}
+		state.triggered = true
+
+		// As the order has been triggered during the candlestick, it can
+		// only be filled at its limit price
+		if order.Side == interfaces.OrderSideBuy && c.Low <= order.Price ||
+			order.Side == interfaces.OrderSideSell && c.High >= order.Price {
+			return order.Price, false, true
+		}
+	}
+
+	return 0, false, false
+}
+
+func limitExecutionPrice(order *interfaces.Order, c models.CandleStick) (price float64, maker, ok bool) {
+	if order.Side == interfaces.OrderSideBuy {
+		if c.Open <= order.Price {
+			return c.Open, false, true
+		} else if c.Low <= order.Price {
+			return order.Price, true, true
+		}
+	} else {
+		if c.Open >= order.Price {
+			return c.Open, false, true
+		} else if c.High >= order.Price {
+			return order.Price, true, true
+		}
+	}
+
+	return 0, false, false
+}
+
+func stopTriggered(order *interfaces.Order, c models.CandleStick) bool {
+	// Stop-loss orders are triggered when the price goes against the
+	// position while take-profit orders are triggered when it goes along
+	rising := order.Side == interfaces.OrderSideBuy
+	if order.Type == interfaces.OrderTypeTakeProfitLimit {
+		rising = !rising
+	}
+
+	if rising {
+		return c.High >= order.StopPrice
+	}
+	return c.Low <= order.StopPrice
+}
+
+// execute will fill the quantity of the order at the price and update the
+// account balances accordingly
+func (p *PaperExchange) execute(order *interfaces.Order, quantity, price float64, maker bool) {
+	base, quote, _ := p.assets(order.Symbol)
+	state := p.states[order.ID]
+
+	makerFee, takerFee := p.account.commissions()
+	fee := takerFee
+	if maker {
+		fee = makerFee
+	}
+
+	// Release the part of the locked amount corresponding to the quantity
+	remaining := order.Quantity - order.ExecutedQuantity
+	release := p.releasable(order, quantity)
+	state.locked -= release
+
+	var commission float64
+	var commissionAsset string
+	var balances []interfaces.Balance
+	if order.Side == interfaces.OrderSideBuy {
+		commission, commissionAsset = quantity*fee, base
+		balances = []interfaces.Balance{
+			p.account.update(quote, release-quantity*price, -release),
+			p.account.update(base, quantity-commission, 0),
+		}
+	} else {
+		commission, commissionAsset = quantity*price*fee, quote
+		balances = []interfaces.Balance{
+			p.account.update(base, quantity-release, -release),
+			p.account.update(quote, quantity*price-commission, 0),
+		}
+	}
+
+	order.ExecutedQuantity += quantity
+	order.CumulativeQuoteQuantity += quantity * price
+	order.UpdateTime = p.now
+	if quantity >= remaining {
+		order.Status = interfaces.OrderStatusFilled
+	} else {
+		order.Status = interfaces.OrderStatusPartiallyFilled
+	}
+
+	report := executionReport(order, interfaces.ExecutionTypeTrade, p.now)
+	report.ExecutionReport.LastExecutedQuantity = quantity
+	report.ExecutionReport.LastExecutedPrice = price
+	report.ExecutionReport.Commission = commission
+	report.ExecutionReport.CommissionAsset = commissionAsset
+	report.ExecutionReport.IsMaker = maker
+	report.ExecutionReport.Exact = executionReportDecimals(report.ExecutionReport)
+	p.notify(report)
+
+	exactBalances := make([]interfaces.ExactBalance, len(balances))
+	for i, b := range balances {
+		exactBalances[i] = interfaces.NewExactBalance(b)
+	}
+	p.notify(interfaces.UserDataEvent{
+		Type: interfaces.UserDataEventTypeAccountPosition,
+		AccountPosition: &interfaces.AccountPosition{
+			EventTime:      p.now,
+			LastUpdateTime: p.now,
+			Balances:       balances,
+			ExactBalances:  exactBalances,
+		},
+	})
+}
+
+// releasable will return the part of the amount locked by the order that
+// corresponds to the quantity
+func (p *PaperExchange) releasable(order *interfaces.Order, quantity float64) float64 {
+	state := p.states[order.ID]
+
+	remaining := order.Quantity - order.ExecutedQuantity
+	if quantity >= remaining {
+		return state.locked
+	}
+	return state.locked * quantity / remaining
+}
+
+// affordable will return if the quantity of the order can be paid at the
+// price. Market buy orders lock their amount at the last close price and can
+// be filled at a higher open price, the difference is then taken from the
+// free balance.
+func (p *PaperExchange) affordable(order *interfaces.Order, quantity, price float64) bool {
+	if order.Type != interfaces.OrderTypeMarket || order.Side != interfaces.OrderSideBuy {
+		return true
+	}
+
+	_, quote, _ := p.assets(order.Symbol)
+	return quantity*price <= p.releasable(order, quantity)+p.account.balance(quote).Free
+}
+
+// expire will expire the order, as Binance does for market orders that can
+// not be paid, and unlock the balance remaining locked by the order
+func (p *PaperExchange) expire(order *interfaces.Order) {
+	order.Status = interfaces.OrderStatusExpired
+	order.UpdateTime = p.now
+
+	if state, ok := p.states[order.ID]; ok {
+		p.account.update(state.asset, state.locked, -state.locked)
+		state.locked = 0
+	}
+
+	p.notify(executionReport(order, interfaces.ExecutionTypeExpired, p.now))
+}
+
+// orderCreated will lock the balance needed by the order
+func (p *PaperExchange) orderCreated(order *interfaces.Order) error {
+	base, quote, err := p.assets(order.Symbol)
+	if err != nil {
+		return err
+	}
+
+	order.Time = p.now
+	order.UpdateTime = p.now
+
+	// Get the asset and amount to lock
+	state := &paperOrder{asset: base, locked: order.Quantity}
+	if order.Side == interfaces.OrderSideBuy {
+		price := order.Price
+		if order.Type == interfaces.OrderTypeMarket {
+			var ok bool
+			if price, ok = p.lastPrice(order.Symbol); !ok {
+				return ErrPaperNoPrice
+			}
+		}
+
+		state.asset, state.locked = quote, order.Quantity*price
+	}
+
+	if p.account.balance(state.asset).Free < state.locked {
+		return ErrInsufficientBalance
+	}
+
+	p.account.update(state.asset, -state.locked, state.locked)
+	p.states[order.ID] = state
+
+	p.notify(executionReport(order, interfaces.ExecutionTypeNew, p.now))
+	return nil
+}
+
+// orderFilled will fill the order as a taker, as when time advances, when it
+// is filled manually with FillOrder
+func (p *PaperExchange) orderFilled(order *interfaces.Order, quantity, price float64) error {
+	if !p.affordable(order, quantity, price) {
+		return ErrInsufficientBalance
+	}
+
+	p.execute(order, quantity, price, false)
+	return nil
+}
+
+// orderCanceled will unlock the balance remaining locked by the order
+func (p *PaperExchange) orderCanceled(order *interfaces.Order) {
+	order.UpdateTime = p.now
+
+	if state, ok := p.states[order.ID]; ok {
+		p.account.update(state.asset, state.locked, -state.locked)
+		state.locked = 0
+	}
+
+	p.notify(executionReport(order, interfaces.ExecutionTypeCanceled, p.now))
+}
+
+// notify will send the event to the user data streams, without blocking and
+// keeping the events order
+func (p *PaperExchange) notify(e interfaces.UserDataEvent) {
+	p.eventsMutex.Lock()
+	defer p.eventsMutex.Unlock()
+
+	p.events = append(p.events, e)
+	if !p.dispatching {
+		p.dispatching = true
+		go p.dispatch()
+	}
+}
+
+func (p *PaperExchange) dispatch() {
+	for {
+		p.eventsMutex.Lock()
+		if len(p.events) == 0 {
+			p.dispatching = false
+			p.eventsMutex.Unlock()
+			return
+		}
+		e := p.events[0]
+		p.events = p.events[1:]
+		p.eventsMutex.Unlock()
+
+		p.userData.push(e)
+	}
+}
+
+func executionReport(order *interfaces.Order, executionType interfaces.ExecutionType, t time.Time) interfaces.UserDataEvent {
+	r := &interfaces.ExecutionReport{
+		EventTime:                t,
+		Symbol:                   order.Symbol,
+		ClientOrderID:            order.ClientOrderID,
+		Side:                     order.Side,
+		Type:                     order.Type,
+		TimeInForce:              order.TimeInForce,
+		Quantity:                 order.Quantity,
+		Price:                    order.Price,
+		StopPrice:                order.StopPrice,
+		ExecutionType:            executionType,
+		Status:                   order.Status,
+		OrderID:                  order.ID,
+		CumulativeFilledQuantity: order.ExecutedQuantity,
+		TransactionTime:          t,
+		OrderCreationTime:        order.Time,
+		CumulativeQuoteQuantity:  order.CumulativeQuoteQuantity,
+	}
+	r.Exact = executionReportDecimals(r)
+
+	return interfaces.UserDataEvent{
+		Type:            interfaces.UserDataEventTypeExecutionReport,
+		ExecutionReport: r,
+	}
+}
+
+// executionReportDecimals will return the shortest decimal representations
+// of the execution report values
+func executionReportDecimals(r *interfaces.ExecutionReport) interfaces.ExecutionReportDecimals {
+	return interfaces.ExecutionReportDecimals{
+		Quantity:                 interfaces.DecimalFromFloat(r.Quantity),
+		Price:                    interfaces.DecimalFromFloat(r.Price),
+		StopPrice:                interfaces.DecimalFromFloat(r.StopPrice),
+		LastExecutedQuantity:     interfaces.DecimalFromFloat(r.LastExecutedQuantity),
+		CumulativeFilledQuantity: interfaces.DecimalFromFloat(r.CumulativeFilledQuantity),
+		LastExecutedPrice:        interfaces.DecimalFromFloat(r.LastExecutedPrice),
+		Commission:               interfaces.DecimalFromFloat(r.Commission),
+		CumulativeQuoteQuantity:  interfaces.DecimalFromFloat(r.CumulativeQuoteQuantity),
+	}
+}
@@ -0,0 +1,324 @@
+package mock
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/models.go"
+)
+
+var testPaperStart = time.Unix(1257894000, 0)
+
+// newTestPaperExchange will create a paper exchange with candles of one
+// minute starting at testPaperStart, with prices 100, 110, 90, 120
+func newTestPaperExchange(volumes []float64) *PaperExchange {
+	p := NewPaperExchange(models.M1, testPaperStart)
+	p.AddVolumeCandleSticks([]VolumeCandleSticks{{
+		Symbol: "BTC-USDC",
+		Period: models.M1,
+		CandleSticks: []models.CandleStick{
+			{Time: testPaperStart, Open: 100, High: 105, Low: 95, Close: 100},
+			{Time: testPaperStart.Add(time.Minute), Open: 100, High: 112, Low: 99, Close: 110},
+			{Time: testPaperStart.Add(2 * time.Minute), Open: 110, High: 111, Low: 88, Close: 90},
+			{Time: testPaperStart.Add(3 * time.Minute), Open: 90, High: 125, Low: 90, Close: 120},
+		},
+		Volumes: volumes,
+	}})
+	return p
+}
+
+func createTestPaperOrder(t *testing.T, p *PaperExchange, r interfaces.OrderRequest) interfaces.Order {
+	o, err := p.NewCreateOrderService().
+		Symbol(r.Symbol).
+		Side(r.Side).
+		Type(r.Type).
+		Quantity(r.Quantity).
+		Price(r.Price).
+		StopPrice(r.StopPrice).
+		Do(context.TODO())
+	if err != nil {
+		t.Fatal("There should be no error on order creation:", err)
+	}
+	return o
+}
+
+func getTestPaperOrder(p *PaperExchange, id int64) interfaces.Order {
+	o, _ := p.NewGetOrderService().Symbol("BTC-USDC").OrderID(id).Do(context.TODO())
+	return o
+}
+
+func checkTestPaperBalance(t *testing.T, p *PaperExchange, asset string, free, locked float64) {
+	b := p.Balance(asset)
+	if math.Abs(b.Free-free) > 1e-9 || math.Abs(b.Locked-locked) > 1e-9 {
+		t.Error("Balance of", asset, "should be", free, "free and", locked, "locked but is", b)
+	}
+}
+
+func TestPaperExchangeMarketOrder(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("USDC", 1000, 0)
+	p.SetFeeRate(0.001)
+	if err := p.Advance(testPaperStart.Add(time.Minute)); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	// Market buy is locked at last close price and filled at next open
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideBuy, Type: interfaces.OrderTypeMarket, Quantity: 2,
+	})
+	checkTestPaperBalance(t, p, "USDC", 800, 200)
+
+	_ = p.Advance(testPaperStart.Add(2 * time.Minute))
+	o = getTestPaperOrder(p, o.ID)
+	if o.Status != interfaces.OrderStatusFilled || o.CumulativeQuoteQuantity != 200 {
+		t.Error("Order should be filled at 100:", o)
+	}
+	checkTestPaperBalance(t, p, "USDC", 800, 0)
+	checkTestPaperBalance(t, p, "BTC", 1.998, 0)
+
+	// Market sell
+	o = createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideSell, Type: interfaces.OrderTypeMarket, Quantity: 1,
+	})
+	checkTestPaperBalance(t, p, "BTC", 0.998, 1)
+
+	_ = p.Advance(testPaperStart.Add(3 * time.Minute))
+	checkTestPaperBalance(t, p, "BTC", 0.998, 0)
+	checkTestPaperBalance(t, p, "USDC", 800+110*0.999, 0)
+}
+
+func TestPaperExchangeMarketOrder_GapUp(t *testing.T) {
+	cases := []struct {
+		Balance float64
+		Status  interfaces.OrderStatus
+		Free    float64
+		BTC     float64
+	}{
+		// Locked amount and free balance can pay the fill at 120
+		{Balance: 300, Status: interfaces.OrderStatusFilled, Free: 60, BTC: 2},
+		// Locked amount can't pay the fill at 120 without free balance
+		{Balance: 200, Status: interfaces.OrderStatusExpired, Free: 200, BTC: 0},
+	}
+
+	for _, c := range cases {
+		p := NewPaperExchange(models.M1, testPaperStart)
+		p.AddCandleSticks([]CandleSticks{{
+			Symbol: "BTC-USDC",
+			Period: models.M1,
+			CandleSticks: []models.CandleStick{
+				{Time: testPaperStart, Open: 100, High: 100, Low: 100, Close: 100},
+				{Time: testPaperStart.Add(time.Minute), Open: 120, High: 120, Low: 120, Close: 120},
+			},
+		}})
+		p.SetBalance("USDC", c.Balance, 0)
+		_ = p.Advance(testPaperStart.Add(time.Minute))
+
+		// Market buy is locked at 100 and filled at 120
+		o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+			Symbol: "BTC-USDC", Side: interfaces.OrderSideBuy, Type: interfaces.OrderTypeMarket, Quantity: 2,
+		})
+		checkTestPaperBalance(t, p, "USDC", c.Balance-200, 200)
+
+		_ = p.Advance(testPaperStart.Add(2 * time.Minute))
+		if o = getTestPaperOrder(p, o.ID); o.Status != c.Status {
+			t.Error("Order should be", c.Status, "with a balance of", c.Balance, "but is", o)
+		}
+		checkTestPaperBalance(t, p, "USDC", c.Free, 0)
+		checkTestPaperBalance(t, p, "BTC", c.BTC, 0)
+	}
+}
+
+func TestPaperExchangeLimitOrder(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("USDC", 1000, 0)
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideBuy, Type: interfaces.OrderTypeLimit, Quantity: 1, Price: 92,
+	})
+	checkTestPaperBalance(t, p, "USDC", 908, 92)
+
+	// Price doesn't reach 92 on the first two candles
+	_ = p.Advance(testPaperStart.Add(2 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusNew {
+		t.Error("Order should not be filled:", o)
+	}
+
+	_ = p.Advance(testPaperStart.Add(3 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusFilled || o.CumulativeQuoteQuantity != 92 {
+		t.Error("Order should be filled at 92:", o)
+	}
+	checkTestPaperBalance(t, p, "USDC", 908, 0)
+	checkTestPaperBalance(t, p, "BTC", 1, 0)
+}
+
+func TestPaperExchangeLimitOrder_Cancel(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("BTC", 1, 0)
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideSell, Type: interfaces.OrderTypeLimit, Quantity: 1, Price: 200,
+	})
+	checkTestPaperBalance(t, p, "BTC", 0, 1)
+
+	if _, err := p.NewCancelOrderService().Symbol("BTC-USDC").OrderID(o.ID).Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	checkTestPaperBalance(t, p, "BTC", 1, 0)
+}
+
+func TestPaperExchangeFillOrder(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("BTC", 1, 0)
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideSell, Type: interfaces.OrderTypeLimit, Quantity: 1, Price: 200,
+	})
+
+	if err := p.FillOrder(o.ID, 0.5, 210); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusPartiallyFilled {
+		t.Error("Order should be partially filled:", o)
+	}
+	checkTestPaperBalance(t, p, "BTC", 0, 0.5)
+	checkTestPaperBalance(t, p, "USDC", 105, 0)
+
+	// Quantity is limited to the remaining one
+	if err := p.FillOrder(o.ID, 1, 210); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusFilled || o.ExecutedQuantity != 1 {
+		t.Error("Order should be filled:", o)
+	}
+	checkTestPaperBalance(t, p, "BTC", 0, 0)
+	checkTestPaperBalance(t, p, "USDC", 210, 0)
+}
+
+func TestPaperExchangeStopLossLimitOrder(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("BTC", 1, 0)
+	_ = p.Advance(testPaperStart.Add(2 * time.Minute))
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideSell, Type: interfaces.OrderTypeStopLossLimit,
+		Quantity: 1, Price: 94, StopPrice: 95,
+	})
+
+	_ = p.Advance(testPaperStart.Add(3 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusFilled || o.CumulativeQuoteQuantity != 94 {
+		t.Error("Order should be filled at 94:", o)
+	}
+	checkTestPaperBalance(t, p, "USDC", 94, 0)
+}
+
+func TestPaperExchangeTakeProfitLimitOrder(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("BTC", 1, 0)
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideSell, Type: interfaces.OrderTypeTakeProfitLimit,
+		Quantity: 1, Price: 115, StopPrice: 115,
+	})
+
+	_ = p.Advance(testPaperStart.Add(3 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusNew {
+		t.Error("Order should not be filled:", o)
+	}
+
+	_ = p.Advance(testPaperStart.Add(4 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusFilled || o.CumulativeQuoteQuantity != 115 {
+		t.Error("Order should be filled at 115:", o)
+	}
+}
+
+func TestPaperExchangePartialFills(t *testing.T) {
+	p := newTestPaperExchange([]float64{10, 10, 10, 10})
+	p.SetBalance("USDC", 1000, 0)
+	p.SetVolumeRatio(0.5)
+	_ = p.Advance(testPaperStart.Add(time.Minute))
+
+	o := createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideBuy, Type: interfaces.OrderTypeMarket, Quantity: 8,
+	})
+
+	_ = p.Advance(testPaperStart.Add(2 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusPartiallyFilled || o.ExecutedQuantity != 5 {
+		t.Error("Order should be partially filled with 5:", o)
+	}
+	checkTestPaperBalance(t, p, "BTC", 5, 0)
+
+	_ = p.Advance(testPaperStart.Add(3 * time.Minute))
+	if o = getTestPaperOrder(p, o.ID); o.Status != interfaces.OrderStatusFilled || o.ExecutedQuantity != 8 {
+		t.Error("Order should be filled:", o)
+	}
+	checkTestPaperBalance(t, p, "BTC", 8, 0)
+	checkTestPaperBalance(t, p, "USDC", 1000-5*100-3*110, 0)
+}
+
+func TestPaperExchangeErrors(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("USDC", 50, 0)
+
+	r := p.NewCreateOrderService().Symbol("BTC-USDC").Side(interfaces.OrderSideBuy).Quantity(1)
+	if _, err := r.Type(interfaces.OrderTypeMarket).Do(context.TODO()); err != ErrPaperNoPrice {
+		t.Error("There should be an error on price, but there is", err)
+	}
+
+	if _, err := r.Type(interfaces.OrderTypeLimit).Price(100).Do(context.TODO()); err != ErrInsufficientBalance {
+		t.Error("There should be an error on balance, but there is", err)
+	}
+
+	r = p.NewCreateOrderService().Symbol("BTCUSDC").Side(interfaces.OrderSideBuy).Type(interfaces.OrderTypeLimit).Quantity(1).Price(10)
+	if _, err := r.Do(context.TODO()); err != ErrPaperUnknownSymbol {
+		t.Error("There should be an error on symbol, but there is", err)
+	}
+
+	p.SetSymbolAssets("BTCUSDC", "BTC", "USDC")
+	if _, err := r.Do(context.TODO()); err != nil {
+		t.Error("There should be no error, but there is", err)
+	}
+
+	if len(p.Orders()) != 1 {
+		t.Error("There should be only 1 order, but there is", len(p.Orders()))
+	}
+
+	if err := p.Advance(testPaperStart.Add(-time.Minute)); err != ErrPaperTimeBackward {
+		t.Error("There should be an error on time, but there is", err)
+	}
+}
+
+func TestPaperExchangeUserDataEvents(t *testing.T) {
+	p := newTestPaperExchange(nil)
+	p.SetBalance("USDC", 1000, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := p.NewUserDataStreamService().Do(ctx)
+
+	createTestPaperOrder(t, p, interfaces.OrderRequest{
+		Symbol: "BTC-USDC", Side: interfaces.OrderSideBuy, Type: interfaces.OrderTypeLimit, Quantity: 1, Price: 100,
+	})
+	_ = p.Advance(testPaperStart.Add(time.Minute))
+
+	expected := []interfaces.UserDataEventType{
+		interfaces.UserDataEventTypeExecutionReport,
+		interfaces.UserDataEventTypeExecutionReport,
+		interfaces.UserDataEventTypeAccountPosition,
+	}
+	for i, et := range expected {
+		select {
+		case e := <-events:
+			if e.Type != et {
+				t.Error("Event", i, "should be", et, "but is", e.Type)
+			}
+			if i == 1 && e.ExecutionReport.ExecutionType != interfaces.ExecutionTypeTrade {
+				t.Error("Execution report should be a trade:", e.ExecutionReport)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("No event received")
+		}
+	}
+}
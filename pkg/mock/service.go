@@ -1,33 +1,236 @@
 package mock
 
 import (
+	"sync"
+
 	interfaces "github.com/cryptellation/binance.go/pkg/binance"
 )
 
-// MockedService represents the Binance service mocked
+// MockedService represents the Binance service mocked, it is safe for
+// concurrent use
 type MockedService struct {
-	candleSticks []CandleSticks
-	nextError    error
+	mutex sync.RWMutex
+
+	candles   *candleStore
+	account   *account
+	orders    *orders
+	userData  *userData
+	injector  *injector
+	calls     *calls
+	clock     *clock
+	nextError error
 }
 
-// New will create a mocked service
+// New will create a mocked service. Market orders are filled at once at the
+// close price of the last candlestick of their symbol, they stay new until
+// filled with FillOrder if there is no candlestick for their symbol.
 func New() *MockedService {
-	return &MockedService{}
+	m := &MockedService{
+		candles:  newCandleStore(),
+		account:  newAccount(),
+		orders:   newOrders(),
+		userData: newUserData(),
+		injector: newInjector(),
+		calls:    newCalls(),
+		clock:    newClock(),
+	}
+	m.orders.lastPrice = m.candles.lastClose
+	return m
 }
 
 // NewCandleStickService will create a new candlestick service
 func (m *MockedService) NewCandleStickService() interfaces.CandleStickServiceInterface {
-	candleService := newCandleStickService(m.candleSticks)
-	candleService.SetError(m.nextError)
+	candleService := newCandleStickService(nil)
+	candleService.store = m.candles
+	candleService.SetError(m.error())
+	candleService.injector = m.injector
+	candleService.calls = m.calls
 	return candleService
 }
 
+// NewAccountService will create a new account service
+func (m *MockedService) NewAccountService() interfaces.AccountServiceInterface {
+	accountService := newAccountService(m.account)
+	accountService.SetError(m.error())
+	accountService.injector = m.injector
+	accountService.calls = m.calls
+	return accountService
+}
+
+// NewCreateOrderService will create a new order creation service
+func (m *MockedService) NewCreateOrderService() interfaces.CreateOrderServiceInterface {
+	orderService := newCreateOrderService(m.orders)
+	orderService.SetError(m.error())
+	orderService.injector = m.injector
+	orderService.calls = m.calls
+	return orderService
+}
+
+// NewGetOrderService will create a new order query service
+func (m *MockedService) NewGetOrderService() interfaces.GetOrderServiceInterface {
+	orderService := newGetOrderService(m.orders)
+	orderService.SetError(m.error())
+	orderService.injector = m.injector
+	orderService.calls = m.calls
+	return orderService
+}
+
+// NewCancelOrderService will create a new order cancellation service
+func (m *MockedService) NewCancelOrderService() interfaces.CancelOrderServiceInterface {
+	orderService := newCancelOrderService(m.orders)
+	orderService.SetError(m.error())
+	orderService.injector = m.injector
+	orderService.calls = m.calls
+	return orderService
+}
+
+// NewCancelOpenOrdersService will create a new open orders cancellation service
+func (m *MockedService) NewCancelOpenOrdersService() interfaces.CancelOpenOrdersServiceInterface {
+	orderService := newCancelOpenOrdersService(m.orders)
+	orderService.SetError(m.error())
+	orderService.injector = m.injector
+	orderService.calls = m.calls
+	return orderService
+}
+
+// NewListOpenOrdersService will create a new open orders listing service
+func (m *MockedService) NewListOpenOrdersService() interfaces.ListOpenOrdersServiceInterface {
+	orderService := newListOpenOrdersService(m.orders)
+	orderService.SetError(m.error())
+	orderService.injector = m.injector
+	orderService.calls = m.calls
+	return orderService
+}
+
+// NewUserDataStreamService will create a new user data stream service
+func (m *MockedService) NewUserDataStreamService() interfaces.UserDataStreamServiceInterface {
+	streamService := newUserDataStreamService(m.userData)
+	streamService.SetError(m.error())
+	streamService.injector = m.injector
+	streamService.calls = m.calls
+	return streamService
+}
+
 // AddCandleSticks will add fake candlesticks to service that can be used in candlestick services
+// Candlesticks of an existing symbol and period are merged into it, the added
+// candlesticks replacing the ones with the same time. It can be used to feed
+// candlesticks while they are requested.
 func (m *MockedService) AddCandleSticks(cs []CandleSticks) {
-	m.candleSticks = append(m.candleSticks, cs...)
+	m.candles.add(withoutVolumes(cs))
+}
+
+// AddVolumeCandleSticks will add fake candlesticks with their volumes to
+// service, as AddCandleSticks
+func (m *MockedService) AddVolumeCandleSticks(cs []VolumeCandleSticks) {
+	m.candles.add(cs)
+}
+
+// ReplaceCandleSticks will replace the candlesticks of the symbols and periods
+// of the given candlesticks
+func (m *MockedService) ReplaceCandleSticks(cs []CandleSticks) {
+	m.candles.replace(withoutVolumes(cs))
+}
+
+// RemoveCandleSticks will remove the candlesticks of the symbol and period
+func (m *MockedService) RemoveCandleSticks(symbol string, period int64) {
+	m.candles.remove(symbol, period)
+}
+
+// ClearCandleSticks will remove every candlesticks
+func (m *MockedService) ClearCandleSticks() {
+	m.candles.clear()
+}
+
+// CandleSticks will return the candlesticks of the service, by symbol and
+// period in their insertion order. They should not be modified.
+func (m *MockedService) CandleSticks() []CandleSticks {
+	series := m.candles.snapshot()
+
+	list := make([]CandleSticks, len(series))
+	for i, cs := range series {
+		list[i] = cs.WithoutVolumes()
+	}
+	return list
+}
+
+// VolumeCandleSticks will return the candlesticks of the service with their
+// volumes, as CandleSticks. They should not be modified.
+func (m *MockedService) VolumeCandleSticks() []VolumeCandleSticks {
+	return m.candles.snapshot()
+}
+
+// SetBalance will set the free and locked amounts of an asset on the account
+func (m *MockedService) SetBalance(asset string, free, locked float64) {
+	m.account.mutex.Lock()
+	defer m.account.mutex.Unlock()
+
+	m.account.balances[asset] = interfaces.Balance{
+		Asset:  asset,
+		Free:   free,
+		Locked: locked,
+	}
+}
+
+// UpdateBalance will add the free and locked deltas (that can be negative) to
+// the corresponding asset balance on the account
+func (m *MockedService) UpdateBalance(asset string, freeDelta, lockedDelta float64) {
+	m.account.update(asset, freeDelta, lockedDelta)
+}
+
+// Balance will return the current balance of an asset on the account
+func (m *MockedService) Balance(asset string) interfaces.Balance {
+	return m.account.balance(asset)
+}
+
+// SetCommissions will set the maker and taker commission rates of the account
+func (m *MockedService) SetCommissions(maker, taker float64) {
+	m.account.mutex.Lock()
+	defer m.account.mutex.Unlock()
+
+	m.account.info.MakerCommission = maker
+	m.account.info.TakerCommission = taker
+}
+
+// SetPermissions will set the permissions of the account
+func (m *MockedService) SetPermissions(canTrade, canWithdraw, canDeposit bool) {
+	m.account.mutex.Lock()
+	defer m.account.mutex.Unlock()
+
+	m.account.info.CanTrade = canTrade
+	m.account.info.CanWithdraw = canWithdraw
+	m.account.info.CanDeposit = canDeposit
+}
+
+// Orders will return every orders that have been created on the service,
+// in their creation order
+func (m *MockedService) Orders() []interfaces.Order {
+	return m.orders.all()
+}
+
+// FillOrder will execute the quantity (limited to the remaining one) of an
+// open order at the given price and update its status accordingly. On a
+// PaperExchange, the balances are also updated as for the fills made when
+// time advances.
+func (m *MockedService) FillOrder(id int64, quantity, price float64) error {
+	return m.orders.fill(id, quantity, price)
+}
+
+// PushUserDataEvent will send the event to every running user data stream
+func (m *MockedService) PushUserDataEvent(e interfaces.UserDataEvent) {
+	m.userData.push(e)
 }
 
 // NextError will set an error for the next Do() on any child service
 func (m *MockedService) NextError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.nextError = err
 }
+
+func (m *MockedService) error() error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.nextError
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/models.go"
 )
 
 func TestNewService(t *testing.T) {
@@ -15,6 +19,19 @@ func TestNewService(t *testing.T) {
 	}
 }
 
+func TestNow(t *testing.T) {
+	var s interfaces.ServiceInterface = New()
+	if d := time.Since(s.Now()); d < 0 || d > time.Second {
+		t.Error("Mocked service time should be the local time but is", s.Now())
+	}
+
+	// Paper exchange time is its virtual time
+	s = NewPaperExchange(models.M1, testPaperStart)
+	if !s.Now().Equal(testPaperStart) {
+		t.Error("Paper exchange time should be", testPaperStart, "but is", s.Now())
+	}
+}
+
 func TestNewCandleStickService(t *testing.T) {
 	// Create a mock service
 	m := New()
@@ -31,6 +48,22 @@ func TestNewCandleStickService(t *testing.T) {
 	}
 }
 
+func TestNewAccountService(t *testing.T) {
+	// Create a mock service
+	m := New()
+
+	// Check nil on new account service
+	s := m.NewAccountService()
+	if s == nil {
+		t.Fatal("New account service should not be nil")
+	}
+
+	// Check type
+	if _, ok := s.(*AccountService); !ok {
+		t.Fatal("Service is not the good type")
+	}
+}
+
 func TestAddCandleSticks(t *testing.T) {
 	m := New()
 	m.AddCandleSticks(TestCandleSticks)
@@ -0,0 +1,214 @@
+package mock
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cryptellation/models.go"
+)
+
+// seriesKey identifies a candlesticks series
+type seriesKey struct {
+	symbol string
+	period int64
+}
+
+// candleStore is the concurrency-safe candlesticks state shared between the
+// mocked service and its candlestick services. Stored series are never
+// modified in place, so snapshots can be read without lock.
+type candleStore struct {
+	mutex  sync.RWMutex
+	keys   []seriesKey
+	series map[seriesKey]VolumeCandleSticks
+}
+
+func newCandleStore(cs ...CandleSticks) *candleStore {
+	s := &candleStore{
+		series: make(map[seriesKey]VolumeCandleSticks),
+	}
+	s.add(withoutVolumes(cs))
+	return s
+}
+
+// withoutVolumes will wrap the candlesticks as candlesticks without volume
+func withoutVolumes(list []CandleSticks) []VolumeCandleSticks {
+	vcs := make([]VolumeCandleSticks, len(list))
+	for i, cs := range list {
+		vcs[i] = VolumeCandleSticks{
+			Symbol:       cs.Symbol,
+			Period:       cs.Period,
+			CandleSticks: cs.CandleSticks,
+		}
+	}
+	return vcs
+}
+
+// add will merge the candlesticks into the stored series. Stored
+// candlesticks with the same time as added ones are replaced by them, while
+// the added candlesticks are all kept.
+func (s *candleStore) add(list []VolumeCandleSticks) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, cs := range list {
+		key := seriesKey{symbol: cs.Symbol, period: cs.Period}
+		stored, exists := s.series[key]
+		if !exists {
+			s.keys = append(s.keys, key)
+		}
+		s.series[key] = mergeCandleSticks(stored, cs)
+	}
+}
+
+// replace will replace the stored series by the candlesticks
+func (s *candleStore) replace(list []VolumeCandleSticks) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, cs := range list {
+		key := seriesKey{symbol: cs.Symbol, period: cs.Period}
+		if _, exists := s.series[key]; !exists {
+			s.keys = append(s.keys, key)
+		}
+		s.series[key] = newSeries(cs.Symbol, cs.Period, entries(cs))
+	}
+}
+
+// remove will remove the series of the symbol and period
+func (s *candleStore) remove(symbol string, period int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := seriesKey{symbol: symbol, period: period}
+	if _, exists := s.series[key]; !exists {
+		return
+	}
+
+	delete(s.series, key)
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// clear will remove every series
+func (s *candleStore) clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys = nil
+	s.series = make(map[seriesKey]VolumeCandleSticks)
+}
+
+// snapshot will return the stored series, in their insertion order
+func (s *candleStore) snapshot() []VolumeCandleSticks {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	list := make([]VolumeCandleSticks, len(s.keys))
+	for i, k := range s.keys {
+		list[i] = s.series[k]
+	}
+	return list
+}
+
+// lastClose will return the close price of the latest candlestick of the
+// symbol, in any period, the last added one if several have the same time
+func (s *candleStore) lastClose(symbol string) (float64, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var last *models.CandleStick
+	for _, k := range s.keys {
+		if k.symbol != symbol {
+			continue
+		}
+
+		cs := s.series[k].CandleSticks
+		for i := range cs {
+			if last == nil || !cs[i].Time.Before(last.Time) {
+				last = &cs[i]
+			}
+		}
+	}
+
+	if last == nil {
+		return 0, false
+	}
+	return last.Close, true
+}
+
+// candleEntry is a candlestick with its volume, if any
+type candleEntry struct {
+	candle    models.CandleStick
+	volume    float64
+	hasVolume bool
+}
+
+// entries will return the entries of the candlesticks, with times in UTC as
+// returned by Binance
+func entries(cs VolumeCandleSticks) []candleEntry {
+	list := make([]candleEntry, len(cs.CandleSticks))
+	for i, c := range cs.CandleSticks {
+		c.Time = c.Time.UTC()
+		volume, ok := cs.Volume(i)
+		list[i] = candleEntry{candle: c, volume: volume, hasVolume: ok}
+	}
+	return list
+}
+
+// mergeCandleSticks will return a new series with the added candlesticks and
+// the stored ones that have not the time of an added one
+func mergeCandleSticks(stored, added VolumeCandleSticks) VolumeCandleSticks {
+	addedEntries := entries(added)
+
+	times := make(map[int64]struct{}, len(addedEntries))
+	for _, e := range addedEntries {
+		times[e.candle.Time.UnixNano()] = struct{}{}
+	}
+
+	list := make([]candleEntry, 0, len(stored.CandleSticks)+len(addedEntries))
+	for _, e := range entries(stored) {
+		if _, replaced := times[e.candle.Time.UnixNano()]; !replaced {
+			list = append(list, e)
+		}
+	}
+	list = append(list, addedEntries...)
+
+	return newSeries(added.Symbol, added.Period, list)
+}
+
+// newSeries will return the series of the entries sorted chronologically,
+// entries with the same time being kept in their order. Volumes are kept if
+// any entry has one.
+func newSeries(symbol string, period int64, list []candleEntry) VolumeCandleSticks {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].candle.Time.Before(list[j].candle.Time)
+	})
+
+	series := VolumeCandleSticks{
+		Symbol:       symbol,
+		Period:       period,
+		CandleSticks: make([]models.CandleStick, len(list)),
+	}
+
+	for _, e := range list {
+		if e.hasVolume {
+			series.Volumes = make([]float64, len(list))
+			series.noVolume = make([]bool, len(list))
+			break
+		}
+	}
+
+	for i, e := range list {
+		series.CandleSticks[i] = e.candle
+		if series.Volumes != nil {
+			series.Volumes[i] = e.volume
+			series.noVolume[i] = !e.hasVolume
+		}
+	}
+
+	return series
+}
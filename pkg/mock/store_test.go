@@ -0,0 +1,144 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/models.go"
+)
+
+func TestAddCandleSticks_Merge(t *testing.T) {
+	m := New()
+	m.AddCandleSticks([]CandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(120, 0), Close: 2},
+		},
+	}})
+	m.AddCandleSticks([]CandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(120, 0), Close: 20},
+			{Time: time.Unix(60, 0), Close: 1},
+		},
+	}})
+
+	series := m.CandleSticks()
+	if len(series) != 1 {
+		t.Fatal("There should be 1 series but there is", len(series))
+	}
+
+	cs := series[0].CandleSticks
+	expected := []float64{1, 20}
+	if len(cs) != len(expected) {
+		t.Fatal("There should be", len(expected), "candlesticks but there is", len(cs))
+	}
+	for i, e := range expected {
+		if cs[i].Close != e {
+			t.Error("Candlestick", i, "close should be", e, "but is", cs[i].Close)
+		}
+	}
+}
+
+func TestAddCandleSticks_MergeVolumes(t *testing.T) {
+	s := newCandleStore()
+	s.add([]VolumeCandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(60, 0), Close: 1},
+			{Time: time.Unix(120, 0), Close: 2},
+		},
+	}})
+	s.add([]VolumeCandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Close: 0},
+			{Time: time.Unix(120, 0), Close: 20},
+		},
+		Volumes: []float64{5, 6},
+	}})
+
+	series := s.snapshot()
+	if len(series) != 1 {
+		t.Fatal("There should be 1 series but there is", len(series))
+	}
+
+	cs := series[0]
+	expected := []float64{0, 1, 20}
+	if len(cs.CandleSticks) != len(expected) {
+		t.Fatal("There should be", len(expected), "candlesticks but there is", len(cs.CandleSticks))
+	}
+	for i, e := range expected {
+		if cs.CandleSticks[i].Close != e {
+			t.Error("Candlestick", i, "close should be", e, "but is", cs.CandleSticks[i].Close)
+		}
+	}
+
+	if v, ok := cs.Volume(2); !ok || v != 6 {
+		t.Error("Volume of replaced candlestick should be 6 but is", v)
+	}
+	if v, ok := cs.Volume(0); !ok || v != 5 {
+		t.Error("Volume of added candlestick should be 5 but is", v)
+	}
+	if _, ok := cs.Volume(1); ok {
+		t.Error("Candlestick without volume should have no volume")
+	}
+}
+
+func TestReplaceCandleSticks(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+	m.ReplaceCandleSticks([]CandleSticks{{
+		Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Close: 42},
+		},
+	}})
+
+	cs, _ := m.NewCandleStickService().Symbol("BTC-USDC").Period(models.M1).Do(context.TODO())
+	if len(cs) != 1 || cs[0].Close != 42 {
+		t.Error("Candlesticks should be replaced but are", cs)
+	}
+}
+
+func TestRemoveAndClearCandleSticks(t *testing.T) {
+	m := New()
+	m.AddCandleSticks(TestCandleSticks)
+
+	m.RemoveCandleSticks("BTC-USDC", models.M1)
+	cs, _ := m.NewCandleStickService().Symbol("BTC-USDC").Do(context.TODO())
+	if len(cs) != 2 {
+		t.Error("There should be 2 candlesticks left for BTC-USDC but there is", len(cs))
+	}
+
+	m.ClearCandleSticks()
+	if n := len(m.CandleSticks()); n != 0 {
+		t.Error("There should be no series but there is", n)
+	}
+}
+
+func TestCandleSticks_Concurrent(t *testing.T) {
+	m := New()
+	service := m.NewCandleStickService().Symbol("BTC-USDC").Period(models.M1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			m.AddCandleSticks([]CandleSticks{{
+				Symbol: "BTC-USDC", Period: models.M1, CandleSticks: []models.CandleStick{
+					{Time: time.Unix(int64(60*i), 0)},
+				},
+			}})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, _ = m.NewCandleStickService().Do(context.TODO())
+			m.NextError(nil)
+		}()
+	}
+	wg.Wait()
+
+	cs, err := service.Do(context.TODO())
+	if err != nil || len(cs) != 10 {
+		t.Error("There should be 10 candlesticks but there is", len(cs), err)
+	}
+}
@@ -0,0 +1,160 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// userDataEventsBufferSize is the size of the user data events channels
+const userDataEventsBufferSize = 64
+
+// userDataSubscriber is a running mocked user data stream
+type userDataSubscriber struct {
+	ctx    context.Context
+	mutex  sync.Mutex
+	closed bool
+	events chan interfaces.UserDataEvent
+}
+
+// send will send the event to the subscriber, waiting until it is read or
+// the subscriber context is done
+func (s *userDataSubscriber) send(e interfaces.UserDataEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.events <- e:
+	case <-s.ctx.Done():
+	}
+}
+
+// close will close the events channel, once no event is being sent
+func (s *userDataSubscriber) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	close(s.events)
+}
+
+// userData is the user data streams state shared between the mocked service
+// and its user data stream services
+type userData struct {
+	mutex       sync.Mutex
+	subscribers map[*userDataSubscriber]struct{}
+}
+
+func newUserData() *userData {
+	return &userData{
+		subscribers: make(map[*userDataSubscriber]struct{}),
+	}
+}
+
+func (u *userData) subscribe(ctx context.Context) <-chan interfaces.UserDataEvent {
+	sub := &userDataSubscriber{
+		ctx:    ctx,
+		events: make(chan interfaces.UserDataEvent, userDataEventsBufferSize),
+	}
+
+	u.mutex.Lock()
+	u.subscribers[sub] = struct{}{}
+	u.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		u.mutex.Lock()
+		delete(u.subscribers, sub)
+		u.mutex.Unlock()
+
+		sub.close()
+	}()
+
+	return sub.events
+}
+
+// push will send the event to every subscriber. Events are sent outside of
+// the lock, so a subscriber that does not read its events only blocks the
+// push, not the other subscribers.
+func (u *userData) push(e interfaces.UserDataEvent) {
+	u.mutex.Lock()
+	subscribers := make([]*userDataSubscriber, 0, len(u.subscribers))
+	for sub := range u.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	u.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		sub.send(e)
+	}
+}
+
+// UserDataStreamService is the mocked service for user data stream
+type UserDataStreamService struct {
+	userData *userData
+
+	keepAlive  time.Duration
+	errHandler func(error)
+	err        error
+	injector   *injector
+	calls      *calls
+}
+
+func newUserDataStreamService(u *userData) *UserDataStreamService {
+	return &UserDataStreamService{
+		userData:  u,
+		keepAlive: interfaces.DefaultUserDataStreamKeepAlive,
+	}
+}
+
+// Do will start the user data stream and return the channel where the events
+// pushed on the mocked service will be sent, until the context is done
+func (m *UserDataStreamService) Do(ctx context.Context) (<-chan interfaces.UserDataEvent, error) {
+	start := time.Now()
+	res, err := m.do(ctx)
+	m.calls.record(Call{Operation: OpUserDataStream}, start, 0, err)
+	return res, err
+}
+
+func (m *UserDataStreamService) do(ctx context.Context) (<-chan interfaces.UserDataEvent, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if err := m.injector.inject(ctx, OpUserDataStream, ""); err != nil {
+		return nil, err
+	}
+
+	if m.keepAlive <= 0 {
+		return nil, interfaces.ErrUserDataInvalidKeepAlive
+	}
+
+	return m.userData.subscribe(ctx), nil
+}
+
+// KeepAlive will specify the interval between two listen key keepalives, it
+// should be positive
+func (m *UserDataStreamService) KeepAlive(interval time.Duration) interfaces.UserDataStreamServiceInterface {
+	m.keepAlive = interval
+	return m
+}
+
+// ErrorHandler will specify a function that will be called with every error
+// happening on the stream after it has started
+func (m *UserDataStreamService) ErrorHandler(handler func(error)) interfaces.UserDataStreamServiceInterface {
+	m.errHandler = handler
+	return m
+}
+
+// SetError will set an error that will be raised each time a Do() is executed
+// You can set it at nil if you want to deactivate it
+func (m *UserDataStreamService) SetError(err error) {
+	m.err = err
+}
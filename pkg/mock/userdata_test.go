@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	interfaces "github.com/cryptellation/binance.go/pkg/binance"
+)
+
+func TestMockedUserDataStreamDo(t *testing.T) {
+	m := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := m.NewUserDataStreamService().Do(ctx)
+	if err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+
+	e := interfaces.UserDataEvent{
+		Type:          interfaces.UserDataEventTypeBalanceUpdate,
+		BalanceUpdate: &interfaces.BalanceUpdate{Asset: "BTC", Delta: 1},
+	}
+	m.PushUserDataEvent(e)
+
+	select {
+	case r := <-events:
+		if r.Type != e.Type || r.BalanceUpdate != e.BalanceUpdate {
+			t.Error("Event don't correspond: should be", e, "but is", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No event received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("There should be no more event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Channel should be closed")
+	}
+
+	// Check that push does not block without subscribers
+	m.PushUserDataEvent(e)
+}
+
+func TestMockedUserDataStreamDo_SlowSubscriber(t *testing.T) {
+	m := New()
+	e := interfaces.UserDataEvent{Type: interfaces.UserDataEventTypeBalanceUpdate}
+
+	// Fill the events buffer of a subscriber that never reads
+	slowCtx, cancelSlow := context.WithCancel(context.Background())
+	if _, err := m.NewUserDataStreamService().Do(slowCtx); err != nil {
+		t.Fatal("There should be no error:", err)
+	}
+	for i := 0; i < userDataEventsBufferSize; i++ {
+		m.PushUserDataEvent(e)
+	}
+
+	pushed := make(chan struct{})
+	go func() {
+		m.PushUserDataEvent(e)
+		close(pushed)
+	}()
+
+	// Other subscribers should still be able to start and stop
+	started := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, _ = m.NewUserDataStreamService().Do(ctx)
+		cancel()
+		close(started)
+	}()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Subscription should not be blocked by a slow subscriber")
+	}
+
+	cancelSlow()
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("Push should end when the slow subscriber is done")
+	}
+}
+
+func TestMockedUserDataStreamDo_InvalidKeepAlive(t *testing.T) {
+	m := New()
+	if _, err := m.NewUserDataStreamService().KeepAlive(0).Do(context.TODO()); err != interfaces.ErrUserDataInvalidKeepAlive {
+		t.Error("There should be an error on keepalive, but there is", err)
+	}
+}
+
+func TestMockedUserDataStreamDo_Error(t *testing.T) {
+	m := New()
+	m.NextError(errors.New("Some error"))
+	if _, err := m.NewUserDataStreamService().Do(context.TODO()); err == nil {
+		t.Error("There should be an error on user data stream service")
+	}
+}
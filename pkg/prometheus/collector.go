@@ -0,0 +1,268 @@
+// Package prometheus is a metrics collector for the real Binance service that
+// exposes its metrics in Prometheus text exposition format, without depending
+// on Prometheus client library.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cryptellation/binance.go/pkg/binance"
+)
+
+// ContentType is the content type of Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the default buckets of the requests duration histogram,
+// in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+const (
+	// RequestsTotal is the name of the REST requests counter
+	RequestsTotal = "binance_requests_total"
+	// RequestDuration is the name of the REST requests duration histogram
+	RequestDuration = "binance_request_duration_seconds"
+	// UsedWeight is the name of the used weight gauge
+	UsedWeight = "binance_used_weight"
+	// RetriesTotal is the name of the retries counter
+	RetriesTotal = "binance_retries_total"
+	// StreamReconnectsTotal is the name of the streams reconnections counter
+	StreamReconnectsTotal = "binance_stream_reconnects_total"
+	// CandleSticksTotal is the name of the returned candlesticks counter
+	CandleSticksTotal = "binance_candlesticks_total"
+)
+
+// Collector collects the metrics of the real service and exposes them in
+// Prometheus text exposition format. It can be set on the service with
+// binance.WithMetrics and served with its ServeHTTP method.
+type Collector struct {
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+// NewCollector will create a new collector, with the given buckets for the
+// requests duration histogram or DefaultBuckets if none
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	c := &Collector{families: make(map[string]*family)}
+	c.add(RequestsTotal, "Number of REST requests by endpoint and outcome.", "counter", nil, "endpoint", "outcome")
+	c.add(RequestDuration, "Duration of REST requests by endpoint, in seconds.", "histogram", buckets, "endpoint")
+	c.add(UsedWeight, "Request weight used on the interval, as sent by Binance.", "gauge", nil, "interval")
+	c.add(RetriesTotal, "Number of retries by operation.", "counter", nil, "operation")
+	c.add(StreamReconnectsTotal, "Number of streams reconnections by stream.", "counter", nil, "stream")
+	c.add(CandleSticksTotal, "Number of candlesticks returned by symbol and interval.", "counter", nil, "symbol", "interval")
+	return c
+}
+
+// ObserveRequest will count the request and observe its duration
+func (c *Collector) ObserveRequest(endpoint, outcome string, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.families[RequestsTotal].get(endpoint, outcome).value++
+	c.families[RequestDuration].observe(duration.Seconds(), endpoint)
+}
+
+// SetUsedWeight will set the used weight of the interval
+func (c *Collector) SetUsedWeight(interval string, weight int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.families[UsedWeight].get(interval).value = float64(weight)
+}
+
+// AddRetry will count a retry of the operation
+func (c *Collector) AddRetry(operation string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.families[RetriesTotal].get(operation).value++
+}
+
+// AddStreamReconnect will count a reconnection of the stream
+func (c *Collector) AddStreamReconnect(stream string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.families[StreamReconnectsTotal].get(stream).value++
+}
+
+// AddCandleSticks will count the candlesticks returned for the symbol and interval
+func (c *Collector) AddCandleSticks(symbol, interval string, count int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.families[CandleSticksTotal].get(symbol, interval).value += float64(count)
+}
+
+// WriteTo will write the metrics in Prometheus text exposition format, sorted
+// by name and labels
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	names := make([]string, 0, len(c.families))
+	for name := range c.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, name := range names {
+		c.families[name].write(cw)
+	}
+
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP will write the metrics as a Prometheus scrape endpoint
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = c.WriteTo(w)
+}
+
+func (c *Collector) add(name, help, typ string, buckets []float64, labels ...string) {
+	c.families[name] = &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+}
+
+// family is a metric with its series by labels values
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series is the value of a metric for labels values
+type series struct {
+	labels []string
+	// value is the value of counters and gauges, and the sum of histograms
+	value float64
+	// count and counts are the observations count of histograms, in total
+	// and by bucket
+	count  uint64
+	counts []uint64
+}
+
+func (f *family) get(labels ...string) *series {
+	key := strings.Join(labels, "\xff")
+	s, exists := f.series[key]
+	if !exists {
+		s = &series{labels: labels}
+		if f.buckets != nil {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// observe will add the value to the histogram series of the labels, buckets
+// counts are cumulative
+func (f *family) observe(value float64, labels ...string) {
+	s := f.get(labels...)
+	s.value += value
+	s.count++
+	for i, b := range f.buckets {
+		if value <= b {
+			s.counts[i]++
+		}
+	}
+}
+
+func (f *family) write(w *countingWriter) {
+	w.printf("# HELP %s %s\n", f.name, f.help)
+	w.printf("# TYPE %s %s\n", f.name, f.typ)
+
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := f.series[k]
+		labels := f.formatLabels(s.labels)
+
+		if f.buckets == nil {
+			w.printf("%s%s %s\n", f.name, wrapLabels(labels), formatValue(s.value))
+			continue
+		}
+
+		for i, b := range f.buckets {
+			le := fmt.Sprintf("le=%q", formatValue(b))
+			w.printf("%s_bucket%s %d\n", f.name, wrapLabels(labels, le), s.counts[i])
+		}
+		w.printf("%s_bucket%s %d\n", f.name, wrapLabels(labels, `le="+Inf"`), s.count)
+		w.printf("%s_sum%s %s\n", f.name, wrapLabels(labels), formatValue(s.value))
+		w.printf("%s_count%s %d\n", f.name, wrapLabels(labels), s.count)
+	}
+}
+
+func (f *family) formatLabels(values []string) []string {
+	labels := make([]string, len(f.labels))
+	for i, name := range f.labels {
+		labels[i] = name + `="` + escapeLabelValue(values[i]) + `"`
+	}
+	return labels
+}
+
+func wrapLabels(labels []string, extra ...string) string {
+	all := append(append([]string(nil), labels...), extra...)
+	if len(all) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(all, ",") + "}"
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter is a writer keeping the number of written bytes and the
+// first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+// Check that the collector implements the metrics interface
+var _ binance.MetricsInterface = (*Collector)(nil)
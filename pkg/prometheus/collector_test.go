@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/fake"
+	"github.com/cryptellation/binance.go/pkg/mock"
+	"github.com/cryptellation/models.go"
+)
+
+func TestCollector_WriteTo(t *testing.T) {
+	c := NewCollector(0.1, 1)
+	c.ObserveRequest("/api/v3/klines", binance.RequestOutcomeSuccess, 50*time.Millisecond)
+	c.ObserveRequest("/api/v3/klines", binance.RequestOutcomeSuccess, 500*time.Millisecond)
+	c.ObserveRequest("/api/v3/order", binance.RequestOutcomeClientError, 2*time.Second)
+	c.SetUsedWeight("1m", 10)
+	c.SetUsedWeight("1m", 12)
+	c.AddRetry("user_data")
+	c.AddStreamReconnect("user_data")
+	c.AddCandleSticks("BTCUSDT", "1m", 500)
+	c.AddCandleSticks("BTCUSDT", "1m", 20)
+	c.AddCandleSticks(`A"B`, "1h", 1)
+
+	expected := `# HELP binance_candlesticks_total Number of candlesticks returned by symbol and interval.
+# TYPE binance_candlesticks_total counter
+binance_candlesticks_total{symbol="A\"B",interval="1h"} 1
+binance_candlesticks_total{symbol="BTCUSDT",interval="1m"} 520
+# HELP binance_request_duration_seconds Duration of REST requests by endpoint, in seconds.
+# TYPE binance_request_duration_seconds histogram
+binance_request_duration_seconds_bucket{endpoint="/api/v3/klines",le="0.1"} 1
+binance_request_duration_seconds_bucket{endpoint="/api/v3/klines",le="1"} 2
+binance_request_duration_seconds_bucket{endpoint="/api/v3/klines",le="+Inf"} 2
+binance_request_duration_seconds_sum{endpoint="/api/v3/klines"} 0.55
+binance_request_duration_seconds_count{endpoint="/api/v3/klines"} 2
+binance_request_duration_seconds_bucket{endpoint="/api/v3/order",le="0.1"} 0
+binance_request_duration_seconds_bucket{endpoint="/api/v3/order",le="1"} 0
+binance_request_duration_seconds_bucket{endpoint="/api/v3/order",le="+Inf"} 1
+binance_request_duration_seconds_sum{endpoint="/api/v3/order"} 2
+binance_request_duration_seconds_count{endpoint="/api/v3/order"} 1
+# HELP binance_requests_total Number of REST requests by endpoint and outcome.
+# TYPE binance_requests_total counter
+binance_requests_total{endpoint="/api/v3/klines",outcome="success"} 2
+binance_requests_total{endpoint="/api/v3/order",outcome="client_error"} 1
+# HELP binance_retries_total Number of retries by operation.
+# TYPE binance_retries_total counter
+binance_retries_total{operation="user_data"} 1
+# HELP binance_stream_reconnects_total Number of streams reconnections by stream.
+# TYPE binance_stream_reconnects_total counter
+binance_stream_reconnects_total{stream="user_data"} 1
+# HELP binance_used_weight Request weight used on the interval, as sent by Binance.
+# TYPE binance_used_weight gauge
+binance_used_weight{interval="1m"} 12
+`
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	if err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Error("Written bytes should be", buf.Len(), "but is", n)
+	}
+	if buf.String() != expected {
+		t.Error("Output is not correct:\n" + buf.String())
+	}
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	c := NewCollector()
+	c.AddRetry("user_data")
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if rec.Header().Get("Content-Type") != ContentType {
+		t.Error("Content type should be", ContentType, "but is", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `binance_retries_total{operation="user_data"} 1`) {
+		t.Error("Output should contain the retry:\n" + rec.Body.String())
+	}
+}
+
+func TestCollector_Service(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	server.AddCandleSticks([]mock.CandleSticks{{
+		Symbol: "BTCUSDT", Period: models.M1, CandleSticks: []models.CandleStick{
+			{Time: time.Unix(0, 0), Open: 1, High: 1, Low: 1, Close: 1},
+			{Time: time.Unix(60, 0), Open: 1, High: 1, Low: 1, Close: 1},
+		},
+	}})
+
+	c := NewCollector()
+	s := binance.New("key", "secret", append(server.Options(), binance.WithMetrics(c))...)
+
+	if _, err := s.NewCandleStickService().Symbol("BTCUSDT").Period(models.M1).Do(context.TODO()); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+	if _, err := s.NewCandleStickService().Symbol("UNKNOWN").Period(models.M1).Do(context.TODO()); err == nil {
+		t.Fatal("There should be an error on an unknown symbol")
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatal("There should be no error but there is", err)
+	}
+
+	for _, line := range []string{
+		`binance_requests_total{endpoint="/api/v3/klines",outcome="success"} 1`,
+		`binance_requests_total{endpoint="/api/v3/klines",outcome="client_error"} 1`,
+		`binance_request_duration_seconds_count{endpoint="/api/v3/klines"} 2`,
+		`binance_candlesticks_total{symbol="BTCUSDT",interval="1m"} 2`,
+		`binance_used_weight{interval="1m"} `,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Error("Output should contain", line, "but is:\n"+buf.String())
+		}
+	}
+}
@@ -45,10 +45,10 @@ func candlestickTest1(bService binance.ServiceInterface) error {
 	}
 }
 
-func runCandlestickTests(key, secret string) int {
+func runCandlestickTests(key, secret string, options ...binance.Option) int {
 	fmt.Println("Starting Candlestick tests...")
 
-	bService := binance.New(key, secret)
+	bService := binance.New(key, secret, options...)
 
 	count := 0
 	count += errToCount(candlestickTest1(bService))
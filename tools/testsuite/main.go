@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/cryptellation/binance.go/pkg/binance"
+	"github.com/cryptellation/binance.go/pkg/cassette"
 	"github.com/pelletier/go-toml"
 )
 
@@ -27,15 +31,42 @@ func configFromFile(path string) (c config, err error) {
 }
 
 func run() int {
+	modeFlag := flag.String("mode", string(cassette.ModeLive), "live, record or replay mode")
+	cassettePath := flag.String("cassette", "tools/testsuite/testdata/testsuite.json", "cassette file used in record and replay modes")
+	flag.Parse()
+
 	fmt.Println("Running Binance Integration TestSuite")
 
-	conf, err := configFromFile("configs/testsuite.toml")
+	mode, err := cassette.ParseMode(*modeFlag)
 	if err != nil {
-		fmt.Println("Error when reading the configuration:", err)
+		fmt.Println("Error when reading the mode:", err)
 		return 255
 	}
 
-	count := runCandlestickTests(conf.API.Key, conf.API.Secret)
+	// Replayed requests don't need real credentials
+	var conf config
+	if mode != cassette.ModeReplay {
+		conf, err = configFromFile("configs/testsuite.toml")
+		if err != nil {
+			fmt.Println("Error when reading the configuration:", err)
+			return 255
+		}
+	}
+
+	var options []binance.Option
+	if mode != cassette.ModeLive {
+		c, err := cassette.New(*cassettePath, mode, nil)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println("No cassette to replay, it should be recorded first with -mode record:", err)
+			return 255
+		} else if err != nil {
+			fmt.Println("Error when opening the cassette:", err)
+			return 255
+		}
+		options = append(options, binance.WithTransport(c))
+	}
+
+	count := runCandlestickTests(conf.API.Key, conf.API.Secret, options...)
 
 	fmt.Println("Testsuite finished")
 	return count